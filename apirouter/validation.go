@@ -0,0 +1,120 @@
+package apirouter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// RequestValidator checks an incoming request before the route handler runs.
+// pathParams contains the path parameters matched by the framework router.
+// A non nil error rejects the request: see ErrorResponse for how it is written.
+type RequestValidator func(req *http.Request, pathParams map[string]string) error
+
+// ValidatingRouter is implemented by routers able to run a RequestValidator
+// as route middleware.
+type ValidatingRouter[MiddlewareFunc any] interface {
+	ValidationMiddleware(validate RequestValidator) MiddlewareFunc
+}
+
+// ResponseValidator checks the response written by a route handler.
+// pathParams contains the path parameters matched by the framework router.
+// A non nil error discards the response written by the handler, and the error
+// is written in its place: see ErrorResponse.
+type ResponseValidator func(req *http.Request, pathParams map[string]string, status int, header http.Header, body []byte) error
+
+// ResponseValidatingRouter is implemented by routers able to run a
+// ResponseValidator as route middleware.
+type ResponseValidatingRouter[MiddlewareFunc any] interface {
+	ResponseValidationMiddleware(validate ResponseValidator) MiddlewareFunc
+}
+
+// BindingHandlerFunc is a net/http handler which also receives the path
+// parameters matched by the framework router.
+type BindingHandlerFunc func(w http.ResponseWriter, req *http.Request, pathParams map[string]string)
+
+// BindingRouter is implemented by routers able to run a BindingHandlerFunc
+// as route handler.
+type BindingRouter[HandlerFunc any] interface {
+	BindingHandler(handler BindingHandlerFunc) HandlerFunc
+}
+
+// HTTPError is implemented by errors which know the status code to respond with.
+// The error itself is marshalled to JSON as response body.
+type HTTPError interface {
+	error
+	StatusCode() int
+}
+
+// ErrorResponse returns the status code and the JSON body to write when a
+// RequestValidator rejects a request. Errors not implementing HTTPError are
+// reported as internal server errors.
+func ErrorResponse(err error) (int, []byte) {
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		if body, marshalErr := json.Marshal(httpErr); marshalErr == nil {
+			return httpErr.StatusCode(), body
+		}
+	}
+
+	body, _ := json.Marshal(map[string]string{"message": err.Error()})
+	return http.StatusInternalServerError, body
+}
+
+// ResponseBuffer is an http.ResponseWriter keeping the status code and the body
+// in memory, so that a response can be validated before it is sent.
+// Headers are written directly to the wrapped http.ResponseWriter.
+type ResponseBuffer struct {
+	w      http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// NewResponseBuffer returns a ResponseBuffer wrapping w.
+func NewResponseBuffer(w http.ResponseWriter) *ResponseBuffer {
+	return &ResponseBuffer{w: w}
+}
+
+func (b *ResponseBuffer) Header() http.Header {
+	return b.w.Header()
+}
+
+func (b *ResponseBuffer) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *ResponseBuffer) Write(data []byte) (int, error) {
+	b.WriteHeader(http.StatusOK)
+	return b.body.Write(data)
+}
+
+// Status returns the buffered status code. Default to 200 if nothing was written.
+func (b *ResponseBuffer) Status() int {
+	if b.status == 0 {
+		return http.StatusOK
+	}
+	return b.status
+}
+
+// Body returns the buffered body.
+func (b *ResponseBuffer) Body() []byte {
+	return b.body.Bytes()
+}
+
+// Send sends the buffered response to the wrapped http.ResponseWriter.
+func (b *ResponseBuffer) Send() {
+	b.w.WriteHeader(b.Status())
+	b.w.Write(b.body.Bytes())
+}
+
+// SendError discards the buffered response and sends err in its place.
+func (b *ResponseBuffer) SendError(err error) {
+	status, body := ErrorResponse(err)
+	b.w.Header().Del("Content-Length")
+	b.w.Header().Set("Content-Type", "application/json")
+	b.w.WriteHeader(status)
+	b.w.Write(body)
+}
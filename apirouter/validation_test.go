@@ -0,0 +1,96 @@
+package apirouter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testHTTPError struct {
+	Reason string `json:"reason"`
+}
+
+func (e testHTTPError) Error() string   { return e.Reason }
+func (e testHTTPError) StatusCode() int { return http.StatusUnprocessableEntity }
+
+func TestErrorResponse(t *testing.T) {
+	testCases := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "http error",
+			err:            testHTTPError{Reason: "invalid"},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"reason":"invalid"}`,
+		},
+		{
+			name:           "wrapped http error",
+			err:            fmt.Errorf("wrapped: %w", testHTTPError{Reason: "invalid"}),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"reason":"invalid"}`,
+		},
+		{
+			name:           "generic error",
+			err:            errors.New("boom"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"message":"boom"}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			status, body := ErrorResponse(test.err)
+
+			require.Equal(t, test.expectedStatus, status)
+			require.JSONEq(t, test.expectedBody, string(body))
+		})
+	}
+}
+
+func TestResponseBuffer(t *testing.T) {
+	t.Run("sends the buffered response", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		buffer := NewResponseBuffer(w)
+
+		buffer.Header().Set("Content-Type", "text/plain")
+		buffer.WriteHeader(http.StatusCreated)
+		buffer.Write([]byte("created"))
+
+		require.Equal(t, http.StatusCreated, buffer.Status())
+		require.Equal(t, "created", string(buffer.Body()))
+		require.False(t, w.Flushed)
+		require.Empty(t, w.Body.String())
+
+		buffer.Send()
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+		require.Equal(t, "created", w.Body.String())
+	})
+
+	t.Run("default status is 200", func(t *testing.T) {
+		buffer := NewResponseBuffer(httptest.NewRecorder())
+		require.Equal(t, http.StatusOK, buffer.Status())
+	})
+
+	t.Run("sends an error in place of the buffered response", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		buffer := NewResponseBuffer(w)
+
+		buffer.Header().Set("Content-Type", "text/plain")
+		buffer.Header().Set("Content-Length", "7")
+		buffer.Write([]byte("created"))
+
+		buffer.SendError(testHTTPError{Reason: "invalid"})
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		require.Empty(t, w.Header().Get("Content-Length"))
+		require.JSONEq(t, `{"reason":"invalid"}`, w.Body.String())
+	})
+}
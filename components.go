@@ -0,0 +1,353 @@
+package swagger
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ErrComponentNameCollision is returned when distinct Go types would be
+// documented by the same component.
+var ErrComponentNameCollision = errors.New("component name collision")
+
+// ErrComponent is returned when a reusable component is invalid, or when a
+// reference names a component which is not registered.
+var ErrComponent = errors.New("invalid component")
+
+// ComponentNamer returns the component name of a type, or an empty string to
+// use the name of the type.
+type ComponentNamer func(t reflect.Type) string
+
+// PackageQualifiedName is a ComponentNamer naming a component after the
+// package and the name of its type, such as billing.Account.
+func PackageQualifiedName(t reflect.Type) string {
+	pkg := path.Base(t.PkgPath())
+	if t.Name() == "" || pkg == "." || pkg == "/" {
+		return t.Name()
+	}
+	return pkg + "." + t.Name()
+}
+
+// componentTypes tracks the Go types documented by the schema components,
+// so that distinct types never share a component.
+type componentTypes struct {
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}
+
+func newComponentTypes() *componentTypes {
+	return &componentTypes{
+		byName: make(map[string]reflect.Type),
+		byType: make(map[reflect.Type]string),
+	}
+}
+
+// componentNaming names the types reflected for a schema. The names are
+// only committed to the component types once added to the components.
+type componentNaming struct {
+	components *componentTypes
+	namer      func(t reflect.Type) string
+	onConflict ComponentNamer
+	byName     map[string]reflect.Type
+	byType     map[reflect.Type]string
+	err        error
+}
+
+// newComponentNaming returns the naming of the types reflected with namer,
+// renaming with onConflict the types whose name is already used.
+func newComponentNaming(components *componentTypes, namer func(t reflect.Type) string, onConflict ComponentNamer) *componentNaming {
+	return &componentNaming{
+		components: components,
+		namer:      namer,
+		onConflict: onConflict,
+		byName:     make(map[string]reflect.Type),
+		byType:     make(map[reflect.Type]string),
+	}
+}
+
+// owner returns the type documented by the component name, if any.
+func (n *componentNaming) owner(name string) reflect.Type {
+	if t, ok := n.byName[name]; ok {
+		return t
+	}
+	if n.components != nil {
+		return n.components.byName[name]
+	}
+	return nil
+}
+
+// name implements jsonschema.Reflector.Namer.
+func (n *componentNaming) name(t reflect.Type) string {
+	if n.components != nil {
+		if name, ok := n.components.byType[t]; ok {
+			return name
+		}
+	}
+	if name, ok := n.byType[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if n.namer != nil {
+		if customName := n.namer(t); customName != "" {
+			name = customName
+		}
+	}
+	if name == "" {
+		return ""
+	}
+
+	if owner := n.owner(name); owner != nil && owner != t {
+		if n.onConflict != nil {
+			if customName := n.onConflict(t); customName != "" {
+				name = customName
+			}
+		}
+		if owner := n.owner(name); owner != nil && owner != t && n.err == nil {
+			n.err = fmt.Errorf("%w: %s and %s are both named %q", ErrComponentNameCollision, owner, t, name)
+		}
+	}
+	n.byName[name] = t
+	n.byType[t] = name
+	return name
+}
+
+// commit records the type documented by the component name, once added to the components.
+func (n *componentNaming) commit(name string) {
+	t, ok := n.byName[name]
+	if !ok || n.components == nil {
+		return
+	}
+	if _, exists := n.components.byName[name]; !exists {
+		n.components.byName[name] = t
+		n.components.byType[t] = name
+	}
+}
+
+// RefSchema returns a Schema referencing the schema registered as name.
+func RefSchema(name string) Schema {
+	return Schema{Ref: name}
+}
+
+// RefResponse returns a response referencing the response registered as name.
+func RefResponse(name string) ContentValue {
+	return ContentValue{Ref: name}
+}
+
+// RefRequestBody returns a request body referencing the request body
+// registered as name.
+func RefRequestBody(name string) *ContentValue {
+	return &ContentValue{Ref: name}
+}
+
+// RefParameter returns a parameter referencing the parameter registered as name.
+func RefParameter(name string) ParameterDefinition {
+	return ParameterDefinition{Ref: name}
+}
+
+// RefHeader returns a response header referencing the header registered as name.
+func RefHeader(name string) Header {
+	return Header{Ref: name}
+}
+
+// RegisterSchema adds the schema of value to the components of the schema of
+// the router as name, so that it can be referenced with RefSchema. The other
+// occurrences of the type of value reference it too. Groups share the
+// components of their parent, while host routers have their own.
+func (r *Router[_, _, _]) RegisterSchema(name string, value any) error {
+	if name == "" || value == nil {
+		return fmt.Errorf("%w: name and value are required", ErrComponent)
+	}
+	if _, exists := r.components().Schemas[name]; exists {
+		return fmt.Errorf("%w: schema %q already registered", ErrComponent, name)
+	}
+
+	// The component of the type of value is named name, unless the type is
+	// already documented by another component, which is then referenced
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	_, named := r.componentTypes.byType[t]
+	if !named && t.Name() != "" {
+		r.componentTypes.byName[name] = t
+		r.componentTypes.byType[t] = name
+	}
+
+	schema, err := r.getSchemaFromInterface(value, false)
+	if err != nil {
+		if !named {
+			delete(r.componentTypes.byName, name)
+			delete(r.componentTypes.byType, t)
+		}
+		return fmt.Errorf("%w: schema %q: %w", ErrComponent, name, err)
+	}
+	if r.components().Schemas == nil {
+		r.components().Schemas = make(openapi3.Schemas)
+	}
+	if _, exists := r.components().Schemas[name]; !exists {
+		r.components().Schemas[name] = schema
+	}
+	return nil
+}
+
+// RegisterResponse adds response to the components of the schema of the
+// router as name, so that it can be referenced with RefResponse.
+func (r *Router[_, _, _]) RegisterResponse(name string, response ContentValue) error {
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrComponent)
+	}
+	if _, exists := r.components().Responses[name]; exists {
+		return fmt.Errorf("%w: response %q already registered", ErrComponent, name)
+	}
+
+	responseRef, err := r.newResponseRef(response)
+	if err != nil {
+		return fmt.Errorf("%w: response %q: %w", ErrComponent, name, err)
+	}
+	if responseRef.Value != nil && responseRef.Value.Description == nil {
+		responseRef.Value.WithDescription("")
+	}
+	if r.components().Responses == nil {
+		r.components().Responses = make(openapi3.ResponseBodies)
+	}
+	r.components().Responses[name] = responseRef
+	return nil
+}
+
+// RegisterRequestBody adds body to the components of the schema of the
+// router as name, so that it can be referenced with RefRequestBody.
+func (r *Router[_, _, _]) RegisterRequestBody(name string, body ContentValue) error {
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrComponent)
+	}
+	if _, exists := r.components().RequestBodies[name]; exists {
+		return fmt.Errorf("%w: request body %q already registered", ErrComponent, name)
+	}
+
+	bodyRef, err := r.newRequestBodyRef(body)
+	if err != nil {
+		return fmt.Errorf("%w: request body %q: %w", ErrComponent, name, err)
+	}
+	if r.components().RequestBodies == nil {
+		r.components().RequestBodies = make(openapi3.RequestBodies)
+	}
+	r.components().RequestBodies[name] = bodyRef
+	return nil
+}
+
+// RegisterParameter adds the parameter name to the components of the schema
+// of the router, so that it can be referenced with RefParameter. Path
+// parameters are always required.
+func (r *Router[_, _, _]) RegisterParameter(name string, parameter ParameterDefinition) error {
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrComponent)
+	}
+	if _, ok := paramLocationOrder[parameter.In]; !ok && parameter.Ref == "" {
+		return fmt.Errorf("%w: parameter %q has an invalid location %q", ErrComponent, name, parameter.In)
+	}
+	if _, exists := r.components().Parameters[name]; exists {
+		return fmt.Errorf("%w: parameter %q already registered", ErrComponent, name)
+	}
+
+	if parameter.In == pathParamsType {
+		parameter.Required = true
+	}
+	parameterRef, err := r.newParameterRef(name, parameter)
+	if err != nil {
+		return fmt.Errorf("%w: parameter %q: %w", ErrComponent, name, err)
+	}
+	if r.components().Parameters == nil {
+		r.components().Parameters = make(openapi3.ParametersMap)
+	}
+	r.components().Parameters[name] = parameterRef
+	return nil
+}
+
+// RegisterHeader adds the response header header to the components of the
+// schema of the router as name, so that it can be referenced with RefHeader.
+func (r *Router[_, _, _]) RegisterHeader(name string, header Header) error {
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrComponent)
+	}
+	if _, exists := r.components().Headers[name]; exists {
+		return fmt.Errorf("%w: header %q already registered", ErrComponent, name)
+	}
+
+	headerRef, err := r.newHeaderRef(header)
+	if err != nil {
+		return fmt.Errorf("%w: header %q: %w", ErrComponent, name, err)
+	}
+	if r.components().Headers == nil {
+		r.components().Headers = make(openapi3.Headers)
+	}
+	r.components().Headers[name] = headerRef
+	return nil
+}
+
+// components returns the components of the schema of the router.
+func (r *Router[_, _, _]) components() *openapi3.Components {
+	if r.swaggerSchema.Components == nil {
+		r.swaggerSchema.Components = &openapi3.Components{}
+	}
+	return r.swaggerSchema.Components
+}
+
+// componentRef returns the reference to the component name of kind, such as
+// responses.
+func componentRef(kind, name string) string {
+	return "#/components/" + kind + "/" + name
+}
+
+// lookupComponent returns the component name of kind, or an error if it is
+// not registered.
+func lookupComponent[T any](components map[string]*T, kind, name string) (*T, error) {
+	component, ok := components[name]
+	if !ok || component == nil {
+		return nil, fmt.Errorf("%w: %s %q not registered", ErrComponent, kind, name)
+	}
+	return component, nil
+}
+
+func (r Router[_, _, _]) schemaComponent(name string) (*openapi3.SchemaRef, error) {
+	var components openapi3.Schemas
+	if r.swaggerSchema.Components != nil {
+		components = r.swaggerSchema.Components.Schemas
+	}
+	return lookupComponent(components, "schema", name)
+}
+
+func (r Router[_, _, _]) responseComponent(name string) (*openapi3.ResponseRef, error) {
+	var components openapi3.ResponseBodies
+	if r.swaggerSchema.Components != nil {
+		components = r.swaggerSchema.Components.Responses
+	}
+	return lookupComponent(components, "response", name)
+}
+
+func (r Router[_, _, _]) requestBodyComponent(name string) (*openapi3.RequestBodyRef, error) {
+	var components openapi3.RequestBodies
+	if r.swaggerSchema.Components != nil {
+		components = r.swaggerSchema.Components.RequestBodies
+	}
+	return lookupComponent(components, "request body", name)
+}
+
+func (r Router[_, _, _]) parameterComponent(name string) (*openapi3.ParameterRef, error) {
+	var components openapi3.ParametersMap
+	if r.swaggerSchema.Components != nil {
+		components = r.swaggerSchema.Components.Parameters
+	}
+	return lookupComponent(components, "parameter", name)
+}
+
+func (r Router[_, _, _]) headerComponent(name string) (*openapi3.HeaderRef, error) {
+	var components openapi3.Headers
+	if r.swaggerSchema.Components != nil {
+		components = r.swaggerSchema.Components.Headers
+	}
+	return lookupComponent(components, "header", name)
+}
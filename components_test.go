@@ -0,0 +1,190 @@
+package swagger
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.lumeweb.com/gswagger/support/gorilla"
+)
+
+type Cookie struct {
+	Flavor string `json:"flavor"`
+}
+
+type Basket struct {
+	Cookies []Cookie      `json:"cookies"`
+	Session []http.Cookie `json:"session"`
+}
+
+func TestComponentNameCollisions(t *testing.T) {
+	setup := func(t *testing.T, namer ComponentNamer) *TestRouter {
+		t.Helper()
+
+		router, err := NewRouter(gorilla.NewRouter(mux.NewRouter()), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Openapi:        getBaseSwagger(t),
+			ComponentNamer: namer,
+		})
+		require.NoError(t, err)
+		return router
+	}
+	response := func(v any) Definitions {
+		return Definitions{
+			Responses: map[int]ContentValue{
+				200: {Content: Content{jsonType: {Value: v}}},
+			},
+		}
+	}
+	responseRef := func(t *testing.T, router *TestRouter, path string) string {
+		t.Helper()
+		return router.GetSwaggerSchema().Paths.Value(path).Get.Responses.Status(200).Value.Content.Get(jsonType).Schema.Ref
+	}
+
+	t.Run("collisions are reported", func(t *testing.T) {
+		router := setup(t, nil)
+
+		_, err := router.AddRoute(http.MethodGet, "/cookies", okHandler, response(Cookie{}))
+		require.NoError(t, err)
+		_, err = router.AddRoute(http.MethodGet, "/cookies", okHandler, response(Cookie{}))
+		require.NoError(t, err)
+
+		_, err = router.AddRoute(http.MethodGet, "/session", okHandler, response(http.Cookie{}))
+		require.ErrorContains(t, err, `component name collision: swagger.Cookie and http.Cookie are both named "Cookie"`)
+	})
+
+	t.Run("collisions in the same type are reported", func(t *testing.T) {
+		router := setup(t, nil)
+
+		_, err := router.getSchemaFromInterface(Basket{}, false)
+		require.ErrorIs(t, err, ErrComponentNameCollision)
+	})
+
+	t.Run("colliding types are renamed", func(t *testing.T) {
+		router := setup(t, PackageQualifiedName)
+
+		_, err := router.AddRoute(http.MethodGet, "/cookies", okHandler, response(Cookie{}))
+		require.NoError(t, err)
+		_, err = router.AddRoute(http.MethodGet, "/session", okHandler, response(http.Cookie{}))
+		require.NoError(t, err)
+		_, err = router.AddRoute(http.MethodGet, "/basket", okHandler, response(Basket{}))
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		require.Equal(t, "#/components/schemas/Cookie", responseRef(t, router, "/cookies"))
+		require.Equal(t, "#/components/schemas/http.Cookie", responseRef(t, router, "/session"))
+		schemas := router.GetSwaggerSchema().Components.Schemas
+		require.Contains(t, schemas["Cookie"].Value.Properties, "flavor")
+		require.Contains(t, schemas["http.Cookie"].Value.Properties, "Name")
+		require.Equal(t, "#/components/schemas/http.Cookie", schemas["Basket"].Value.Properties["session"].Value.Items.Ref)
+	})
+
+	t.Run("renamed types must not collide", func(t *testing.T) {
+		router := setup(t, func(reflect.Type) string { return "Cookie" })
+
+		_, err := router.AddRoute(http.MethodGet, "/cookies", okHandler, response(Cookie{}))
+		require.NoError(t, err)
+		_, err = router.AddRoute(http.MethodGet, "/session", okHandler, response(http.Cookie{}))
+		require.ErrorContains(t, err, "component name collision")
+	})
+}
+
+type Problem struct {
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+}
+
+type Problems struct {
+	Errors []Problem `json:"errors"`
+}
+
+func TestRegisteredComponents(t *testing.T) {
+	setup := func(t *testing.T) *TestRouter {
+		t.Helper()
+
+		router := setupRouter(t)
+		require.NoError(t, router.RegisterSchema("Error", Problem{}))
+		require.NoError(t, router.RegisterResponse("NotFound", ContentValue{
+			Description: "Resource not found",
+			Content:     Content{jsonType: RefSchema("Error")},
+		}))
+		require.NoError(t, router.RegisterRequestBody("Note", ContentValue{
+			Content:  Content{jsonType: {Value: Note{}}},
+			Required: true,
+		}))
+		require.NoError(t, router.RegisterParameter("limit", ParameterDefinition{
+			In:     queryParamType,
+			Schema: &Schema{Value: 0},
+		}))
+		require.NoError(t, router.RegisterHeader("RateLimit", Header{
+			Schema:      &Schema{Value: 0},
+			Description: "Remaining requests",
+		}))
+		return router
+	}
+
+	t.Run("components are referenced", func(t *testing.T) {
+		router := setup(t)
+
+		_, err := router.AddRoute(http.MethodPost, "/notes", okHandler, Definitions{
+			Parameters:  map[string]ParameterDefinition{"limit": RefParameter("limit")},
+			Headers:     ParameterValue{"X-Trace": {Schema: &Schema{Value: ""}}},
+			RequestBody: RefRequestBody("Note"),
+			Responses: map[int]ContentValue{
+				201: {
+					Content:           Content{jsonType: {Value: Note{}}},
+					HeaderDefinitions: map[string]Header{"X-RateLimit-Remaining": RefHeader("RateLimit")},
+				},
+				404: RefResponse("NotFound"),
+				422: {Content: Content{jsonType: {Value: Problems{}}}},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		schema := router.GetSwaggerSchema()
+		operation := schema.Paths.Value("/notes").Post
+
+		require.Len(t, operation.Parameters, 2)
+		require.Equal(t, "#/components/parameters/limit", operation.Parameters[0].Ref)
+		require.Equal(t, "X-Trace", operation.Parameters[1].Value.Name)
+		require.Equal(t, "#/components/requestBodies/Note", operation.RequestBody.Ref)
+		require.Equal(t, "#/components/responses/NotFound", operation.Responses.Status(404).Ref)
+		require.Equal(t, "#/components/headers/RateLimit", operation.Responses.Status(201).Value.Headers["X-RateLimit-Remaining"].Ref)
+
+		notFound := schema.Components.Responses["NotFound"].Value
+		require.Equal(t, "#/components/schemas/Error", notFound.Content.Get(jsonType).Schema.Ref)
+
+		// Other occurrences of a registered type reference its component
+		problems := operation.Responses.Status(422).Value.Content.Get(jsonType).Schema.Value
+		require.Equal(t, "#/components/schemas/Error", problems.Properties["errors"].Value.Items.Ref)
+		require.NotContains(t, schema.Components.Schemas, "Problem")
+		require.Equal(t, "limit", schema.Components.Parameters["limit"].Value.Name)
+	})
+
+	t.Run("components must be registered", func(t *testing.T) {
+		router := setup(t)
+
+		_, err := router.AddRoute(http.MethodGet, "/notes", okHandler, Definitions{
+			Responses: map[int]ContentValue{404: RefResponse("Gone")},
+		})
+		require.ErrorContains(t, err, `invalid component: response "Gone" not registered`)
+
+		_, err = router.AddRoute(http.MethodGet, "/notes", okHandler, Definitions{
+			Responses: map[int]ContentValue{200: {Content: Content{jsonType: RefSchema("Missing")}}},
+		})
+		require.ErrorContains(t, err, `invalid component: schema "Missing" not registered`)
+	})
+
+	t.Run("components are registered once", func(t *testing.T) {
+		router := setup(t)
+
+		require.ErrorIs(t, router.RegisterSchema("Error", Note{}), ErrComponent)
+		require.ErrorIs(t, router.RegisterResponse("NotFound", ContentValue{}), ErrComponent)
+		require.ErrorIs(t, router.RegisterRequestBody("Note", ContentValue{}), ErrComponent)
+		require.ErrorIs(t, router.RegisterParameter("limit", ParameterDefinition{In: queryParamType}), ErrComponent)
+		require.ErrorIs(t, router.RegisterHeader("RateLimit", Header{}), ErrComponent)
+		require.ErrorIs(t, router.RegisterParameter("page", ParameterDefinition{In: "body"}), ErrComponent)
+	})
+}
@@ -0,0 +1,95 @@
+package swagger
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ErrDefaults is returned when the default definitions of a router are invalid.
+var ErrDefaults = errors.New("invalid default definitions")
+
+// SetDefaults sets the definitions inherited by the routes added afterwards
+// to the router with AddRoute or AddRawRoute, such as the tags, security
+// requirements and error responses shared by the routes of a group.
+// Only Tags, Security, Extensions, Responses and the parameters can be defaults.
+//
+// Definitions are merged in this order, each taking precedence over the previous:
+//   - the defaults of the parent of a group, when the group is created
+//   - the defaults of the router
+//   - the definitions of the route
+//
+// Tags are added before the tags of the route, while the security
+// requirements are replaced when set by the route, as an empty
+// SecurityRequirements to make a route public. Responses, parameters and
+// extensions are added unless the route defines the same status code,
+// parameter location and name, or extension.
+// Host routers do not inherit the defaults of the root router.
+func (r *Router[_, _, _]) SetDefaults(defaults Definitions) error {
+	if defaults.OperationID != "" || defaults.Summary != "" || defaults.Description != "" ||
+		defaults.Deprecated || defaults.RequestBody != nil {
+		return fmt.Errorf("%w: only tags, security, extensions, responses and parameters can be defaults", ErrDefaults)
+	}
+
+	operation, err := r.newOperation("*", r.pathPrefix, defaults)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDefaults, err)
+	}
+	if len(defaults.Responses) == 0 {
+		operation.Responses = nil
+	}
+
+	if r.inheritedDefaults != nil {
+		mergeDefaults(operation.Operation, r.inheritedDefaults)
+	}
+	r.defaults = operation.Operation
+	return nil
+}
+
+// mergeDefaults adds to operation the definitions of defaults it does not define.
+func mergeDefaults(operation, defaults *openapi3.Operation) {
+	if len(defaults.Tags) > 0 {
+		tags := append([]string{}, defaults.Tags...)
+		for _, tag := range operation.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		operation.Tags = tags
+	}
+
+	if operation.Security == nil && defaults.Security != nil {
+		security := append(openapi3.SecurityRequirements{}, *defaults.Security...)
+		operation.Security = &security
+	}
+
+	for _, parameter := range defaults.Parameters {
+		if parameter.Value == nil || operation.Parameters.GetByInAndName(parameter.Value.In, parameter.Value.Name) != nil {
+			continue
+		}
+		operation.Parameters = append(operation.Parameters, parameter)
+	}
+
+	if defaults.Responses != nil {
+		if operation.Responses == nil {
+			operation.Responses = &openapi3.Responses{}
+		}
+		for status, response := range defaults.Responses.Map() {
+			if operation.Responses.Value(status) == nil {
+				operation.Responses.Set(status, response)
+			}
+		}
+	}
+
+	for name, value := range defaults.Extensions {
+		if _, exists := operation.Extensions[name]; exists {
+			continue
+		}
+		if operation.Extensions == nil {
+			operation.Extensions = make(map[string]any, len(defaults.Extensions))
+		}
+		operation.Extensions[name] = value
+	}
+}
@@ -0,0 +1,149 @@
+package swagger
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDefaults(t *testing.T) {
+	unauthorized := ContentValue{Description: "Unauthorized", Content: Content{jsonType: {Value: ""}}}
+	internalError := ContentValue{Description: "Internal error", Content: Content{jsonType: {Value: ""}}}
+
+	adminDefaults := Definitions{
+		Tags:       []string{"admin"},
+		Security:   SecurityRequirements{{"bearer": {}}},
+		Headers:    ParameterValue{"X-Tenant": {Schema: &Schema{Value: ""}, Required: true}},
+		Responses:  map[int]ContentValue{401: unauthorized, 500: internalError},
+		Extensions: map[string]any{"x-internal": true, "x-audience": "staff"},
+	}
+
+	t.Run("routes inherit the defaults of their group", func(t *testing.T) {
+		router := setupRouter(t)
+		require.NoError(t, router.RegisterSecurityScheme("bearer", openapi3.NewJWTSecurityScheme(), nil))
+
+		admin, err := router.Group("/admin")
+		require.NoError(t, err)
+		require.NoError(t, admin.SetDefaults(adminDefaults))
+
+		_, err = admin.AddRoute(http.MethodGet, "/users", okHandler, Definitions{
+			Tags:       []string{"users", "admin"},
+			Headers:    ParameterValue{"X-Tenant": {Schema: &Schema{Value: 0}}},
+			Responses:  map[int]ContentValue{200: {Content: Content{jsonType: {Value: []string{}}}}, 500: {Description: "Database error"}},
+			Extensions: map[string]any{"x-audience": "support"},
+		})
+		require.NoError(t, err)
+		_, err = admin.AddRoute(http.MethodGet, "/health", okHandler, Definitions{
+			Security: SecurityRequirements{},
+		})
+		require.NoError(t, err)
+		_, err = admin.AddRawRoute(http.MethodPost, "/reindex", okHandler, Operation{})
+		require.NoError(t, err)
+		_, err = router.AddRoute(http.MethodGet, "/public", okHandler, Definitions{})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		paths := router.GetSwaggerSchema().Paths
+
+		users := paths.Value("/admin/users").Get
+		require.Equal(t, []string{"admin", "users"}, users.Tags)
+		require.Equal(t, openapi3.SecurityRequirements{{"bearer": {}}}, *users.Security)
+		require.Len(t, users.Parameters, 1)
+		require.True(t, users.Parameters[0].Value.Schema.Value.Type.Is(openapi3.TypeInteger))
+		require.False(t, users.Parameters[0].Value.Required)
+		require.Equal(t, "Unauthorized", *users.Responses.Status(401).Value.Description)
+		require.Equal(t, "Database error", *users.Responses.Status(500).Value.Description)
+		require.NotNil(t, users.Responses.Status(200))
+		require.Equal(t, map[string]any{"x-internal": true, "x-audience": "support"}, users.Extensions)
+
+		health := paths.Value("/admin/health").Get
+		require.Empty(t, *health.Security)
+		require.Equal(t, []string{"admin"}, health.Tags)
+
+		reindex := paths.Value("/admin/reindex").Post
+		require.Equal(t, []string{"admin"}, reindex.Tags)
+		require.NotNil(t, reindex.Security)
+		require.NotNil(t, reindex.Parameters.GetByInAndName(headerParamType, "X-Tenant"))
+		require.NotNil(t, reindex.Responses.Status(401))
+
+		public := paths.Value("/public").Get
+		require.Empty(t, public.Tags)
+		require.Nil(t, public.Security)
+		require.Nil(t, public.Responses.Status(401))
+	})
+
+	t.Run("groups inherit the defaults of their parent", func(t *testing.T) {
+		router := setupRouter(t)
+		require.NoError(t, router.RegisterSecurityScheme("bearer", openapi3.NewJWTSecurityScheme(), nil))
+		require.NoError(t, router.RegisterResponse("Forbidden", ContentValue{Description: "Forbidden"}))
+
+		admin, err := router.Group("/admin")
+		require.NoError(t, err)
+		require.NoError(t, admin.SetDefaults(adminDefaults))
+
+		billing, err := admin.Group("/billing")
+		require.NoError(t, err)
+		require.NoError(t, billing.SetDefaults(Definitions{
+			Tags:      []string{"billing"},
+			Responses: map[int]ContentValue{403: RefResponse("Forbidden"), 500: {Description: "Billing error"}},
+		}))
+
+		_, err = billing.AddRoute(http.MethodGet, "/invoices", okHandler, Definitions{})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		invoices := router.GetSwaggerSchema().Paths.Value("/admin/billing/invoices").Get
+		require.Equal(t, []string{"admin", "billing"}, invoices.Tags)
+		require.NotNil(t, invoices.Security)
+		require.Equal(t, "#/components/responses/Forbidden", invoices.Responses.Status(403).Ref)
+		require.Equal(t, "Billing error", *invoices.Responses.Status(500).Value.Description)
+		require.Equal(t, "Unauthorized", *invoices.Responses.Status(401).Value.Description)
+	})
+
+	t.Run("host routers do not inherit the defaults of the root router", func(t *testing.T) {
+		_, router := setupGorillaHostRouterTest(t)
+		require.NoError(t, router.SetDefaults(Definitions{Tags: []string{"root"}}))
+
+		host, err := router.Host("api.example.com")
+		require.NoError(t, err)
+		require.NoError(t, host.SetDefaults(Definitions{Tags: []string{"api"}}))
+
+		_, err = host.AddRoute(http.MethodGet, "/items", okHandler, Definitions{})
+		require.NoError(t, err)
+		require.NoError(t, host.GenerateAndExposeOpenapi())
+
+		require.Equal(t, []string{"api"}, host.GetSwaggerSchema().Paths.Value("/items").Get.Tags)
+	})
+
+	t.Run("defaults do not leak through shared definitions", func(t *testing.T) {
+		router := setupRouter(t)
+
+		admin, err := router.Group("/admin")
+		require.NoError(t, err)
+		require.NoError(t, admin.SetDefaults(Definitions{Extensions: map[string]any{"x-internal": true}}))
+
+		extensions := map[string]any{"x-audience": "staff"}
+		_, err = admin.AddRoute(http.MethodGet, "/users", okHandler, Definitions{Extensions: extensions})
+		require.NoError(t, err)
+		_, err = router.AddRoute(http.MethodGet, "/public", okHandler, Definitions{Extensions: extensions})
+		require.NoError(t, err)
+
+		paths := router.GetSwaggerSchema().Paths
+		require.Equal(t, map[string]any{"x-internal": true, "x-audience": "staff"}, paths.Value("/admin/users").Get.Extensions)
+		require.Equal(t, map[string]any{"x-audience": "staff"}, paths.Value("/public").Get.Extensions)
+		require.Equal(t, map[string]any{"x-audience": "staff"}, extensions)
+	})
+
+	t.Run("route specific definitions can not be defaults", func(t *testing.T) {
+		router := setupRouter(t)
+
+		err := router.SetDefaults(Definitions{Summary: "Admin route"})
+		require.ErrorIs(t, err, ErrDefaults)
+
+		err = router.SetDefaults(Definitions{Responses: map[int]ContentValue{404: RefResponse("NotFound")}})
+		require.ErrorIs(t, err, ErrDefaults)
+		require.ErrorContains(t, err, `response "NotFound" not registered`)
+	})
+}
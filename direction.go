@@ -0,0 +1,93 @@
+package swagger
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+)
+
+// directionTag is the struct tag setting the direction of a field:
+// `gswagger:"readOnly"` for the fields only sent by the server, such as ids,
+// and `gswagger:"writeOnly"` for the fields only sent by the client, such as passwords.
+const directionTag = "gswagger"
+
+// Options of the direction tag
+const (
+	readOnlyOption  = "readOnly"
+	writeOnlyOption = "writeOnly"
+)
+
+// requestComponentSuffix is appended to the component name of the structs
+// whose schema is different in requests.
+const requestComponentSuffix = "Input"
+
+// schemaDirection is the direction of the data described by a schema.
+type schemaDirection int
+
+const (
+	responseDirection schemaDirection = iota // Data sent by the server
+	requestDirection                         // Data sent by the client
+)
+
+// omittedOption returns the direction option of the fields omitted in d.
+func (d schemaDirection) omittedOption() string {
+	if d == requestDirection {
+		return readOnlyOption
+	}
+	return writeOnlyOption
+}
+
+// fieldDirection returns the direction option of a field, if any.
+func fieldDirection(field reflect.StructField) string {
+	for _, option := range strings.Split(field.Tag.Get(directionTag), ",") {
+		if option == readOnlyOption || option == writeOnlyOption {
+			return option
+		}
+	}
+	return ""
+}
+
+// hasDirectedFields reports whether the struct t, or a struct reachable from
+// its fields, has readOnly or writeOnly fields, so that its schema depends on
+// the direction.
+func hasDirectedFields(t reflect.Type, tagName string) bool {
+	visited := map[reflect.Type]bool{}
+
+	var walk func(t reflect.Type) bool
+	walk = func(t reflect.Type) bool {
+		t = containedType(t, visited)
+		if t.Kind() != reflect.Struct || visited[t] {
+			return false
+		}
+		visited[t] = true
+
+		for _, f := range promotedFields(t, tagName) {
+			if fieldDirection(f.field) != "" || walk(f.field.Type) {
+				return true
+			}
+		}
+		return false
+	}
+	return walk(t)
+}
+
+// markDirectedFields marks the properties of the schema of the struct t
+// which are readOnly or writeOnly. Referenced properties can not be marked.
+func markDirectedFields(schema *jsonschema.Schema, t reflect.Type, tagName string) {
+	if schema.Properties == nil {
+		return
+	}
+	for _, f := range promotedFields(t, tagName) {
+		property, ok := schema.Properties.Get(f.name)
+		if !ok || property.Ref != "" {
+			continue
+		}
+		switch fieldDirection(f.field) {
+		case readOnlyOption:
+			property.ReadOnly = true
+		case writeOnlyOption:
+			property.WriteOnly = true
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package swagger
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Account struct {
+	ID        string    `json:"id" gswagger:"readOnly"`
+	CreatedAt time.Time `json:"createdAt" gswagger:"readOnly"`
+	Email     string    `json:"email"`
+	Password  string    `json:"password" gswagger:"writeOnly"`
+}
+
+type Squad struct {
+	Name    string    `json:"name"`
+	Members []Account `json:"members"`
+}
+
+type Folder struct {
+	Name     string `json:"name"`
+	Children Tree   `json:"children"`
+}
+
+type Note struct {
+	Text string `json:"text"`
+}
+
+func TestDirectedSchemas(t *testing.T) {
+	schemaJSON := func(t *testing.T, router *TestRouter, name string) string {
+		t.Helper()
+
+		schema, ok := router.GetSwaggerSchema().Components.Schemas[name]
+		require.True(t, ok, "missing component %s", name)
+		data, err := json.Marshal(schema.Value)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	router := setupRouter(t)
+	_, err := router.AddRoute(http.MethodPost, "/accounts", okHandler, Definitions{
+		RequestBody: &ContentValue{Content: Content{jsonType: {Value: Account{}}}},
+		Responses: map[int]ContentValue{
+			201: {Content: Content{jsonType: {Value: Account{}}}},
+		},
+	})
+	require.NoError(t, err)
+	_, err = router.AddRoute(http.MethodPut, "/squads", okHandler, Definitions{
+		RequestBody: &ContentValue{Content: Content{jsonType: {Value: Squad{}}}},
+		Responses: map[int]ContentValue{
+			200: {Content: Content{jsonType: {Value: []Squad{}}}},
+		},
+	})
+	require.NoError(t, err)
+	_, err = router.AddRoute(http.MethodPost, "/notes", okHandler, Definitions{
+		RequestBody: &ContentValue{Content: Content{jsonType: {Value: Note{}}}},
+		Responses: map[int]ContentValue{
+			201: {Content: Content{jsonType: {Value: Note{}}}},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, router.GenerateAndExposeOpenapi())
+
+	t.Run("read-only fields are omitted from requests", func(t *testing.T) {
+		require.JSONEq(t, `{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"email": {"type": "string"},
+				"password": {"type": "string", "writeOnly": true}
+			},
+			"required": ["email", "password"]
+		}`, schemaJSON(t, router, "AccountInput"))
+
+		body := router.GetSwaggerSchema().Paths.Value("/accounts").Post.RequestBody.Value.Content.Get(jsonType).Schema
+		require.Equal(t, "#/components/schemas/AccountInput", body.Ref)
+	})
+
+	t.Run("write-only fields are omitted from responses", func(t *testing.T) {
+		require.JSONEq(t, `{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"id": {"type": "string", "readOnly": true},
+				"createdAt": {"type": "string", "format": "date-time", "readOnly": true},
+				"email": {"type": "string"}
+			},
+			"required": ["id", "createdAt", "email"]
+		}`, schemaJSON(t, router, "Account"))
+
+		response := router.GetSwaggerSchema().Paths.Value("/accounts").Post.Responses.Status(201).Value.Content.Get(jsonType).Schema
+		require.Equal(t, "#/components/schemas/Account", response.Ref)
+	})
+
+	t.Run("structs containing directed structs depend on the direction", func(t *testing.T) {
+		require.Contains(t, schemaJSON(t, router, "SquadInput"), `"#/components/schemas/AccountInput"`)
+		require.Contains(t, schemaJSON(t, router, "Squad"), `"#/components/schemas/Account"`)
+	})
+
+	t.Run("other structs are shared", func(t *testing.T) {
+		require.NotContains(t, router.GetSwaggerSchema().Components.Schemas, "NoteInput")
+		require.Contains(t, router.GetSwaggerSchema().Components.Schemas, "Note")
+	})
+	t.Run("structs containing recursive containers", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.AddRoute(http.MethodPost, "/folders", okHandler, Definitions{
+			RequestBody: &ContentValue{Content: Content{jsonType: {Value: Folder{}}}},
+		})
+		require.NoError(t, err)
+		require.NotContains(t, router.GetSwaggerSchema().Components.Schemas, "FolderInput")
+	})
+}
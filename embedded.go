@@ -0,0 +1,364 @@
+package swagger
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+)
+
+var (
+	customSchemaType = reflect.TypeFor[interface{ JSONSchema() *jsonschema.Schema }]()
+	extendSchemaType = reflect.TypeFor[interface{ JSONSchemaExtend(*jsonschema.Schema) }]()
+)
+
+// promotedField is a field of a struct, possibly promoted from an embedded struct.
+type promotedField struct {
+	name   string
+	tagged bool
+	index  []int
+	field  reflect.StructField
+}
+
+// reflectorFieldNameTag returns the tag naming the fields reflected by reflector.
+func reflectorFieldNameTag(reflector *jsonschema.Reflector) string {
+	if reflector.FieldNameTag != "" {
+		return reflector.FieldNameTag
+	}
+	return "json"
+}
+
+// fieldTagName returns the name and the options of a field from its tag.
+func fieldTagName(field reflect.StructField, tagName string) (string, string) {
+	name, options, _ := strings.Cut(field.Tag.Get(tagName), ",")
+	return name, options
+}
+
+// isPromoted reports whether the fields of an embedded struct are promoted,
+// as encoding/json does: the field must be an untagged struct or pointer to struct.
+func isPromoted(field reflect.StructField, tagName string) bool {
+	if !field.Anonymous || field.Tag.Get(tagName) == "-" {
+		return false
+	}
+	if name, _ := fieldTagName(field, tagName); name != "" {
+		return false
+	}
+	t := field.Type
+	if t.Name() == "" && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// hasPromotedFields reports whether t is a struct embedding other structs.
+func hasPromotedFields(t reflect.Type, tagName string) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if isPromoted(t.Field(i), tagName) {
+			return true
+		}
+	}
+	return false
+}
+
+// promotedFields returns the fields of t serialized by encoding/json, including
+// the fields promoted from embedded structs. As in encoding/json, the shallowest
+// field wins over deeper ones, and a tagged field wins over untagged ones at the
+// same depth. Other conflicting fields are dropped.
+func promotedFields(t reflect.Type, tagName string) []promotedField {
+	var fields []promotedField
+	next := []promotedField{{field: reflect.StructField{Type: t}}}
+	visited := map[reflect.Type]bool{}
+	var count map[reflect.Type]int
+	nextCount := map[reflect.Type]int{t: 1}
+
+	for len(next) > 0 {
+		current := next
+		next = nil
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, f := range current {
+			st := f.field.Type
+			if visited[st] {
+				continue
+			}
+			visited[st] = true
+
+			for i := 0; i < st.NumField(); i++ {
+				field := st.Field(i)
+				if field.Anonymous {
+					ft := field.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					if !field.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !field.IsExported() {
+					continue
+				}
+				if field.Tag.Get(tagName) == "-" {
+					continue
+				}
+
+				index := append(append([]int{}, f.index...), i)
+				if isPromoted(field, tagName) {
+					ft := field.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					nextCount[ft]++
+					if nextCount[ft] == 1 {
+						next = append(next, promotedField{index: index, field: reflect.StructField{Type: ft}})
+					}
+					continue
+				}
+
+				name, _ := fieldTagName(field, tagName)
+				promoted := promotedField{
+					name:   name,
+					tagged: name != "",
+					index:  index,
+					field:  field,
+				}
+				if promoted.name == "" {
+					promoted.name = field.Name
+				}
+				fields = append(fields, promoted)
+				if count[st] > 1 {
+					// The same struct embedded twice at this depth: its fields annihilate
+					fields = append(fields, promoted)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].name != fields[j].name {
+			return fields[i].name < fields[j].name
+		}
+		if len(fields[i].index) != len(fields[j].index) {
+			return len(fields[i].index) < len(fields[j].index)
+		}
+		return fields[i].tagged && !fields[j].tagged
+	})
+
+	dominant := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		group := fields[i:j]
+		i = j
+
+		if len(group) > 1 && len(group[0].index) == len(group[1].index) && group[0].tagged == group[1].tagged {
+			continue
+		}
+		dominant = append(dominant, group[0])
+	}
+
+	sort.Slice(dominant, func(i, j int) bool {
+		a, b := dominant[i].index, dominant[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return dominant
+}
+
+// flattenedStruct returns an unnamed struct type with the fields of t
+// serialized by encoding/json, embedded structs and the fields with the
+// omitted direction option excluded.
+func flattenedStruct(t reflect.Type, tagName, omitted string) reflect.Type {
+	fields := promotedFields(t, tagName)
+	structFields := make([]reflect.StructField, 0, len(fields))
+	used := make(map[string]bool, len(fields))
+
+	for i, f := range fields {
+		if fieldDirection(f.field) == omitted {
+			continue
+		}
+		field := reflect.StructField{
+			Name: f.field.Name,
+			Type: f.field.Type,
+			Tag:  f.field.Tag,
+		}
+		if used[field.Name] || !token.IsExported(field.Name) {
+			// Rename the Go field, keeping its serialized name in the tag
+			field.Name = fmt.Sprintf("Field%d", i)
+			_, options := fieldTagName(f.field, tagName)
+			if options != "" {
+				options = "," + options
+			}
+			field.Tag = reflect.StructTag(fmt.Sprintf("%s:%q %s", tagName, f.name+options, f.field.Tag))
+		}
+		used[field.Name] = true
+		structFields = append(structFields, field)
+	}
+	return reflect.StructOf(structFields)
+}
+
+// containedType returns the type contained by the pointers, slices, arrays and
+// maps t is made of, or t. The containers are marked as visited, since a named
+// container may be its own element, as in type Tree map[string]Tree.
+func containedType(t reflect.Type, visited map[reflect.Type]bool) reflect.Type {
+	for !visited[t] && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+		visited[t] = true
+		t = t.Elem()
+	}
+	return t
+}
+
+// checkEmbeddedCycles returns an error if a struct reachable from t embeds
+// itself, directly or through other embedded structs.
+func checkEmbeddedCycles(t reflect.Type, tagName string) error {
+	visited := map[reflect.Type]bool{}
+
+	var embeddingChain func(t reflect.Type, chain []reflect.Type) error
+	embeddingChain = func(t reflect.Type, chain []reflect.Type) error {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !isPromoted(field, tagName) {
+				continue
+			}
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			for _, c := range chain {
+				if c == embedded {
+					names := make([]string, 0, len(chain)+1)
+					for _, c := range append(chain, embedded) {
+						names = append(names, c.String())
+					}
+					return fmt.Errorf("embedded struct %s creates a cycle: %s", field.Type, strings.Join(names, " -> "))
+				}
+			}
+			if err := embeddingChain(embedded, append(chain, embedded)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var walk func(t reflect.Type) error
+	walk = func(t reflect.Type) error {
+		t = containedType(t, visited)
+		if t.Kind() != reflect.Struct || visited[t] {
+			return nil
+		}
+		visited[t] = true
+
+		if err := embeddingChain(t, []reflect.Type{t}); err != nil {
+			return err
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() && !field.Anonymous {
+				continue
+			}
+			if err := walk(field.Type); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if t == nil {
+		return nil
+	}
+	return walk(t)
+}
+
+// structMapper returns a jsonschema Mapper reflecting the structs embedding
+// other structs with the fields promoted following encoding/json rules, the
+// structs with fields depending on the direction without the fields omitted
+// in direction, and, when validatorTag is set, the structs whose fields have
+// validator tags with the matching constraints. It wraps the Mapper of the
+// reflector. Named structs are added to definitions and referenced.
+func structMapper(reflector *jsonschema.Reflector, definitions jsonschema.Definitions, validatorTag string, direction schemaDirection) func(reflect.Type) *jsonschema.Schema {
+	mapper := reflector.Mapper
+	tagName := reflectorFieldNameTag(reflector)
+
+	var mapStruct func(t reflect.Type) *jsonschema.Schema
+	mapStruct = func(t reflect.Type) *jsonschema.Schema {
+		if mapper != nil {
+			if schema := mapper(t); schema != nil {
+				return schema
+			}
+		}
+		if t.Kind() != reflect.Struct || t.Implements(customSchemaType) {
+			return nil
+		}
+		validated := validatorTag != "" && hasValidatorTags(t, tagName, validatorTag)
+		directed := hasDirectedFields(t, tagName)
+		if !validated && !directed && !hasPromotedFields(t, tagName) {
+			return nil
+		}
+
+		name := ""
+		if !reflector.DoNotReference {
+			name = t.Name()
+			if reflector.Namer != nil {
+				if customName := reflector.Namer(t); customName != "" {
+					name = customName
+				}
+			}
+			if name != "" && directed && direction == requestDirection {
+				name += requestComponentSuffix
+			}
+		}
+		if name != "" {
+			if _, exists := definitions[name]; exists {
+				return &jsonschema.Schema{Ref: "#/$defs/" + name}
+			}
+			// Reserve the name, so that recursive references are resolved
+			definitions[name] = &jsonschema.Schema{}
+		}
+
+		flattened := flattenedStruct(t, tagName, direction.omittedOption())
+		flattenedReflector := *reflector
+		flattenedReflector.Mapper = func(t reflect.Type) *jsonschema.Schema {
+			if t == flattened {
+				return nil
+			}
+			return mapStruct(t)
+		}
+		flattenedReflector.ExpandedStruct = false
+		schema := flattenedReflector.ReflectFromType(flattened)
+		for defName, def := range schema.Definitions {
+			if _, exists := definitions[defName]; !exists {
+				definitions[defName] = def
+			}
+		}
+		schema.Definitions = nil
+		schema.Version = ""
+		schema.ID = ""
+
+		if validated {
+			applyValidatorTags(schema, t, tagName, validatorTag)
+		}
+		if directed {
+			markDirectedFields(schema, t, tagName)
+		}
+		if t.Implements(extendSchemaType) {
+			reflect.New(t).Interface().(interface{ JSONSchemaExtend(*jsonschema.Schema) }).JSONSchemaExtend(schema)
+		}
+
+		if name == "" {
+			return schema
+		}
+		*definitions[name] = *schema
+		return &jsonschema.Schema{Ref: "#/$defs/" + name}
+	}
+	return mapStruct
+}
@@ -0,0 +1,162 @@
+package swagger
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Timestamps struct {
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
+
+type Audit struct {
+	Timestamps
+	CreatedBy string `json:"createdBy"`
+}
+
+type pagination struct {
+	Page  int `json:"page"`
+	Total int `json:"total"`
+}
+
+type Article struct {
+	*Audit
+	pagination
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type Labeled struct {
+	Name    string
+	Label   string
+	Caption int `json:"Title"`
+}
+
+type Described struct {
+	Name        string
+	Label       string
+	Title       string
+	Description string `json:"description"`
+}
+
+type Conflicting struct {
+	Labeled
+	Described
+	Label string
+}
+
+// Tree and List are named containers whose element is the container itself.
+type Tree map[string]Tree
+
+type List []List
+
+type Named struct {
+	Timestamps `json:"timestamps"`
+	ID         string `json:"id"`
+}
+
+func TestEmbeddedStructs(t *testing.T) {
+	schemaJSON := func(t *testing.T, router *TestRouter, name string) string {
+		t.Helper()
+
+		data, err := json.Marshal(router.GetSwaggerSchema().Components.Schemas[name].Value)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("promoted fields are flattened", func(t *testing.T) {
+		router := setupRouter(t)
+
+		schema, err := router.getSchemaFromInterface(&Article{}, false)
+		require.NoError(t, err)
+		require.Equal(t, "#/$defs/Article", schema.Ref)
+
+		require.JSONEq(t, `{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"createdAt": {"type": "string", "format": "date-time"},
+				"updatedAt": {"type": "string", "format": "date-time"},
+				"createdBy": {"type": "string"},
+				"page": {"type": "integer"},
+				"total": {"type": "integer"},
+				"id": {"type": "string"},
+				"title": {"type": "string"}
+			},
+			"required": ["createdAt", "createdBy", "page", "total", "id", "title"]
+		}`, schemaJSON(t, router, "Article"))
+		require.NotContains(t, router.GetSwaggerSchema().Components.Schemas, "Audit")
+	})
+
+	t.Run("conflicting fields follow encoding/json rules", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.getSchemaFromInterface(Conflicting{}, false)
+		require.NoError(t, err)
+
+		// Label is defined at the shallowest depth, the tagged Title wins
+		// over the untagged one, and Name conflicts and is dropped
+		require.JSONEq(t, `{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"Title": {"type": "integer"},
+				"description": {"type": "string"},
+				"Label": {"type": "string"}
+			},
+			"required": ["Title", "description", "Label"]
+		}`, schemaJSON(t, router, "Conflicting"))
+	})
+
+	t.Run("tagged embedded struct is a field", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.getSchemaFromInterface(Named{}, false)
+		require.NoError(t, err)
+
+		require.JSONEq(t, `{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"timestamps": {"$ref": "#/$defs/Timestamps"},
+				"id": {"type": "string"}
+			},
+			"required": ["timestamps", "id"]
+		}`, schemaJSON(t, router, "Named"))
+		require.Contains(t, router.GetSwaggerSchema().Components.Schemas, "Timestamps")
+	})
+
+	t.Run("embedded structs in nested types and routes", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.AddRoute(http.MethodGet, "/articles", okHandler, Definitions{
+			Responses: map[int]ContentValue{
+				200: {Content: Content{jsonType: {Value: []Article{}}}},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		response := router.GetSwaggerSchema().Paths.Value("/articles").Get.Responses.Status(200).Value
+		items := response.Content.Get(jsonType).Schema.Value.Items
+		require.Equal(t, "#/components/schemas/Article", items.Ref)
+		require.Contains(t, schemaJSON(t, router, "Article"), `"createdBy"`)
+	})
+	t.Run("recursive containers", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.AddRoute(http.MethodPost, "/trees", okHandler, Definitions{
+			RequestBody: &ContentValue{Content: Content{jsonType: {Value: Tree{}}}},
+			Responses: map[int]ContentValue{
+				200: {Content: Content{jsonType: {Value: List{}}}},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+	})
+}
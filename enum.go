@@ -0,0 +1,155 @@
+package swagger
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/invopop/jsonschema"
+)
+
+// ErrEnum is returned when the values of an enum type are invalid.
+var ErrEnum = errors.New("invalid enum")
+
+// enumVarNamesExtension lists the names of the constants of an enum,
+// in the order of its values, for client generators.
+const enumVarNamesExtension = "x-enum-varnames"
+
+// EnumValuer is implemented by the types whose values are restricted to a set
+// of constants. Every occurrence of such type is documented with an enum.
+// The values must have the type itself, otherwise the type is documented
+// without enum.
+type EnumValuer interface {
+	EnumValues() []any
+}
+
+// EnumVarNamer is implemented by the enum types exposing the names of their
+// constants, in the order of their values, as the x-enum-varnames extension.
+type EnumVarNamer interface {
+	EnumVarNames() []string
+}
+
+var (
+	enumValuerType   = reflect.TypeFor[EnumValuer]()
+	enumVarNamerType = reflect.TypeFor[EnumVarNamer]()
+)
+
+// enumDefinition is the schema of an enum type.
+type enumDefinition struct {
+	values   []any
+	varNames []string
+}
+
+// RegisterEnum registers the values of an enum type, so that every occurrence
+// of the type is documented with an enum. The optional varNames are the names
+// of the constants, in the order of the values, exposed as the x-enum-varnames
+// extension. Values must share the same string, integer, number or boolean type.
+// The registered values take precedence over the EnumValuer implementation of
+// the type. Enums are shared by groups and host routers.
+//
+//	router.RegisterEnum([]any{StatusActive, StatusBanned}, "StatusActive", "StatusBanned")
+func (r *Router[_, _, _]) RegisterEnum(values []any, varNames ...string) error {
+	if len(values) == 0 {
+		return fmt.Errorf("%w: no values", ErrEnum)
+	}
+	t := reflect.TypeOf(values[0])
+	definition, err := newEnumDefinition(t, values, varNames)
+	if err != nil {
+		return err
+	}
+	if _, exists := r.enums[t]; exists {
+		return fmt.Errorf("%w %s: already registered", ErrEnum, t)
+	}
+	r.enums[t] = definition
+	return nil
+}
+
+// newEnumDefinition checks that values and varNames describe the enum type t,
+// and converts the values to their underlying type.
+func newEnumDefinition(t reflect.Type, values []any, varNames []string) (enumDefinition, error) {
+	if t == nil || enumSchemaType(t) == "" {
+		return enumDefinition{}, fmt.Errorf("%w: unsupported type %T", ErrEnum, values[0])
+	}
+	if len(varNames) > 0 && len(varNames) != len(values) {
+		return enumDefinition{}, fmt.Errorf("%w %s: %d values and %d names", ErrEnum, t, len(values), len(varNames))
+	}
+
+	definition := enumDefinition{
+		values:   make([]any, 0, len(values)),
+		varNames: varNames,
+	}
+	for _, value := range values {
+		v := reflect.ValueOf(value)
+		if !v.IsValid() || v.Type() != t {
+			return enumDefinition{}, fmt.Errorf("%w %s: value %v has type %T", ErrEnum, t, value, value)
+		}
+		switch t.Kind() {
+		case reflect.String:
+			definition.values = append(definition.values, v.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			definition.values = append(definition.values, v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			definition.values = append(definition.values, v.Uint())
+		case reflect.Float32, reflect.Float64:
+			definition.values = append(definition.values, v.Float())
+		case reflect.Bool:
+			definition.values = append(definition.values, v.Bool())
+		}
+	}
+	return definition, nil
+}
+
+// enumSchemaType returns the schema type of the values of t,
+// or an empty string if t can not be an enum.
+func enumSchemaType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	}
+	return ""
+}
+
+// enumMapper returns a jsonschema Mapper documenting the registered enum types
+// and the types implementing EnumValuer, wrapping mapper.
+func enumMapper(mapper func(reflect.Type) *jsonschema.Schema, enums map[reflect.Type]enumDefinition) func(reflect.Type) *jsonschema.Schema {
+	return func(t reflect.Type) *jsonschema.Schema {
+		if mapper != nil {
+			if schema := mapper(t); schema != nil {
+				return schema
+			}
+		}
+
+		definition, ok := enums[t]
+		if !ok {
+			if !t.Implements(enumValuerType) || enumSchemaType(t) == "" {
+				return nil
+			}
+			enum := reflect.New(t).Elem().Interface()
+			var varNames []string
+			if t.Implements(enumVarNamerType) {
+				varNames = enum.(EnumVarNamer).EnumVarNames()
+			}
+			var err error
+			definition, err = newEnumDefinition(t, enum.(EnumValuer).EnumValues(), varNames)
+			if err != nil || len(definition.values) == 0 {
+				return nil
+			}
+		}
+
+		schema := &jsonschema.Schema{
+			Type: enumSchemaType(t),
+			Enum: definition.values,
+		}
+		if len(definition.varNames) > 0 {
+			schema.Extras = map[string]any{enumVarNamesExtension: definition.varNames}
+		}
+		return schema
+	}
+}
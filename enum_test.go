@@ -0,0 +1,96 @@
+package swagger
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Status string
+
+const (
+	StatusActive Status = "active"
+	StatusBanned Status = "banned"
+)
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityHigh
+)
+
+func (Priority) EnumValues() []any {
+	return []any{PriorityLow, PriorityHigh}
+}
+
+func (Priority) EnumVarNames() []string {
+	return []string{"PriorityLow", "PriorityHigh"}
+}
+
+type Level int
+
+func (Level) EnumValues() []any {
+	return []any{1, 2}
+}
+
+type Ticket struct {
+	Status   Status   `json:"status"`
+	Priority Priority `json:"priority"`
+	History  []Status `json:"history"`
+	Previous *Status  `json:"previous,omitempty"`
+	Level    Level    `json:"level"`
+}
+
+func TestEnums(t *testing.T) {
+	t.Run("every occurrence of enum types is documented", func(t *testing.T) {
+		router := setupRouter(t)
+		require.NoError(t, router.RegisterEnum([]any{StatusActive, StatusBanned}, "StatusActive", "StatusBanned"))
+
+		_, err := router.AddRoute(http.MethodPost, "/tickets", okHandler, Definitions{
+			Querystring: ParameterValue{
+				"status": {Schema: &Schema{Value: StatusActive}},
+			},
+			RequestBody: &ContentValue{Content: Content{jsonType: {Value: Ticket{}}}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		status := `{"type": "string", "enum": ["active", "banned"], "x-enum-varnames": ["StatusActive", "StatusBanned"]}`
+		data, err := json.Marshal(router.GetSwaggerSchema().Components.Schemas["Ticket"].Value.Properties)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"status": `+status+`,
+			"priority": {"type": "integer", "enum": [0, 1], "x-enum-varnames": ["PriorityLow", "PriorityHigh"]},
+			"history": {"type": "array", "items": `+status+`},
+			"previous": `+status+`,
+			"level": {"type": "integer"}
+		}`, string(data))
+
+		data, err = json.Marshal(router.GetSwaggerSchema().Paths.Value("/tickets").Post.Parameters[0].Value.Schema)
+		require.NoError(t, err)
+		require.JSONEq(t, status, string(data))
+	})
+
+	t.Run("invalid enums", func(t *testing.T) {
+		router := setupRouter(t)
+
+		err := router.RegisterEnum(nil)
+		require.ErrorIs(t, err, ErrEnum)
+
+		err = router.RegisterEnum([]any{Ticket{}})
+		require.EqualError(t, err, "invalid enum: unsupported type swagger.Ticket")
+
+		err = router.RegisterEnum([]any{StatusActive, "banned"})
+		require.EqualError(t, err, "invalid enum swagger.Status: value banned has type string")
+
+		err = router.RegisterEnum([]any{StatusActive, StatusBanned}, "StatusActive")
+		require.EqualError(t, err, "invalid enum swagger.Status: 2 values and 1 names")
+
+		require.NoError(t, router.RegisterEnum([]any{StatusActive}))
+		err = router.RegisterEnum([]any{StatusBanned})
+		require.EqualError(t, err, "invalid enum swagger.Status: already registered")
+	})
+}
@@ -0,0 +1,62 @@
+package swagger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ErrExample is returned when an example value can not be marshalled to JSON.
+var ErrExample = errors.New("invalid example")
+
+// Examples maps example names to Go values, documented as they are encoded
+// by encoding/json.
+type Examples map[string]any
+
+// newOASExamples returns the OpenAPI examples of the given sets of examples.
+// An example of a later set takes precedence over an example with the same name.
+func newOASExamples(sets ...Examples) (openapi3.Examples, error) {
+	merged := Examples{}
+	for _, examples := range sets {
+		for name, value := range examples {
+			merged[name] = value
+		}
+	}
+	if len(merged) == 0 {
+		return nil, nil
+	}
+
+	// Sort example names for consistent errors
+	names := make([]string, 0, len(merged))
+	for name := range merged {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make(openapi3.Examples, len(merged))
+	for _, name := range names {
+		value, err := exampleValue(merged[name])
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrExample, name, err)
+		}
+		result[name] = &openapi3.ExampleRef{Value: openapi3.NewExample(value)}
+	}
+	return result, nil
+}
+
+// exampleValue returns v as decoded from its JSON encoding, so that it is
+// documented and validated as JSON.
+func exampleValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,110 @@
+package swagger
+
+import (
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.lumeweb.com/gswagger/support/gorilla"
+)
+
+type Invoice struct {
+	Number string `json:"number"`
+	Total  int    `json:"total"`
+}
+
+func TestExamples(t *testing.T) {
+	t.Run("examples are documented as JSON", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.AddRoute(http.MethodPost, "/invoices", okHandler, Definitions{
+			Querystring: ParameterValue{
+				"limit": {
+					Schema:   &Schema{Value: 0, Examples: Examples{"small": 10, "large": 100}},
+					Examples: Examples{"small": 1, "default": 20},
+				},
+			},
+			RequestBody: &ContentValue{
+				Content: Content{
+					jsonType: {
+						Value:    Invoice{},
+						Examples: Examples{"paid": Invoice{Number: "INV-2", Total: 0}},
+					},
+				},
+				Examples: Examples{
+					"paid":   Invoice{Number: "INV-1", Total: 0},
+					"unpaid": Invoice{Number: "INV-3", Total: 1200},
+				},
+			},
+			Responses: map[int]ContentValue{
+				201: {
+					Content: Content{jsonType: {Value: Invoice{}, Examples: Examples{"created": &Invoice{Number: "INV-4", Total: 50}}}},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		operation := router.GetSwaggerSchema().Paths.Value("/invoices").Post
+
+		limit := operation.Parameters.GetByInAndName(queryParamType, "limit")
+		require.Len(t, limit.Examples, 3)
+		require.Equal(t, 10.0, limit.Examples["small"].Value.Value)
+		require.Equal(t, 100.0, limit.Examples["large"].Value.Value)
+		require.Equal(t, 20.0, limit.Examples["default"].Value.Value)
+
+		body := operation.RequestBody.Value.Content.Get(jsonType)
+		require.Len(t, body.Examples, 2)
+		require.Equal(t, map[string]any{"number": "INV-2", "total": 0.0}, body.Examples["paid"].Value.Value)
+		require.Equal(t, map[string]any{"number": "INV-3", "total": 1200.0}, body.Examples["unpaid"].Value.Value)
+
+		response := operation.Responses.Status(201).Value.Content.Get(jsonType)
+		require.Equal(t, map[string]any{"number": "INV-4", "total": 50.0}, response.Examples["created"].Value.Value)
+	})
+
+	t.Run("examples must be encoded as JSON", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.AddRoute(http.MethodGet, "/invoices", okHandler, Definitions{
+			Responses: map[int]ContentValue{
+				200: {
+					Content: Content{jsonType: {Value: 0.0, Examples: Examples{"infinite": math.Inf(1)}}},
+				},
+			},
+		})
+		require.ErrorContains(t, err, `invalid example "infinite"`)
+	})
+
+	staleExample := Definitions{
+		RequestBody: &ContentValue{
+			Content: Content{
+				jsonType: {Value: Invoice{}, Examples: Examples{"stale": map[string]any{"number": 3}}},
+			},
+		},
+	}
+
+	t.Run("stale examples are reported", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.AddRoute(http.MethodPost, "/invoices", okHandler, staleExample)
+		require.NoError(t, err)
+
+		err = router.GenerateAndExposeOpenapi()
+		require.ErrorIs(t, err, ErrValidatingOAS)
+		require.ErrorContains(t, err, "stale")
+	})
+
+	t.Run("examples validation can be skipped", func(t *testing.T) {
+		router, err := NewRouter(gorilla.NewRouter(mux.NewRouter()), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Openapi:                getBaseSwagger(t),
+			SkipExamplesValidation: true,
+		})
+		require.NoError(t, err)
+
+		_, err = router.AddRoute(http.MethodPost, "/invoices", okHandler, staleExample)
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+	})
+}
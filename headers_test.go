@@ -0,0 +1,81 @@
+package swagger
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseHeaderDefinitions(t *testing.T) {
+	t.Run("headers are documented with their schema", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.AddRoute(http.MethodGet, "/items", okHandler, Definitions{
+			Responses: map[int]ContentValue{
+				200: {
+					Content: Content{jsonType: {Value: []string{}}},
+					Headers: map[string]string{
+						"X-Request-ID":          "Request identifier",
+						"X-RateLimit-Remaining": "Overridden",
+					},
+					HeaderDefinitions: map[string]Header{
+						"X-RateLimit-Remaining": {
+							Schema:      &Schema{Value: 0},
+							Description: "Remaining requests",
+							Required:    true,
+							Example:     42,
+						},
+						"Retry-After": {
+							Schema: &Schema{Value: time.Time{}},
+						},
+						"X-Legacy-Cursor": {
+							Description: "Use the Link header",
+							Deprecated:  true,
+						},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		headers := router.GetSwaggerSchema().Paths.Value("/items").Get.Responses.Status(200).Value.Headers
+		require.Len(t, headers, 4)
+
+		requestID := headers["X-Request-ID"].Value
+		require.Equal(t, "Request identifier", requestID.Description)
+		require.True(t, requestID.Schema.Value.Type.Is(openapi3.TypeString))
+
+		remaining := headers["X-RateLimit-Remaining"].Value
+		require.Equal(t, "Remaining requests", remaining.Description)
+		require.True(t, remaining.Required)
+		require.Equal(t, 42, remaining.Example)
+		require.True(t, remaining.Schema.Value.Type.Is(openapi3.TypeInteger))
+
+		retryAfter := headers["Retry-After"].Value
+		require.True(t, retryAfter.Schema.Value.Type.Is(openapi3.TypeString))
+		require.Equal(t, "date-time", retryAfter.Schema.Value.Format)
+
+		legacy := headers["X-Legacy-Cursor"].Value
+		require.True(t, legacy.Deprecated)
+		require.True(t, legacy.Schema.Value.Type.Is(openapi3.TypeString))
+	})
+
+	t.Run("invalid header schemas are reported", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.AddRoute(http.MethodGet, "/items", okHandler, Definitions{
+			Responses: map[int]ContentValue{
+				200: {
+					HeaderDefinitions: map[string]Header{
+						"X-Invalid": {Schema: &Schema{Value: OneOf()}},
+					},
+				},
+			},
+		})
+		require.ErrorContains(t, err, `header "X-Invalid"`)
+	})
+}
@@ -7,7 +7,9 @@ import (
 	"net"
 	"net/http"
 	"path"
+	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/invopop/jsonschema"
 
@@ -82,6 +84,37 @@ type Router[HandlerFunc any, MiddlewareFunc any, Route any] struct {
 
 	reflectorOptions *jsonschema.Reflector
 
+	options routerOptions
+
+	// authenticators maps the security schemes of the schema to their Authenticator
+	authenticators map[string]Authenticator
+
+	// enums maps the enum types to their values
+	enums map[reflect.Type]enumDefinition
+
+	// interfaces maps the interface types to their Polymorphic schema
+	interfaces map[reflect.Type]*Polymorphic
+
+	// componentTypes tracks the Go types documented by the components of the schema
+	componentTypes *componentTypes
+
+	// defaults are merged into the operations of the routes
+	defaults *openapi3.Operation
+
+	// inheritedDefaults are the defaults of the parent of a group
+	inheritedDefaults *openapi3.Operation
+
+	// routes lists the routes added to the root router, its groups and its host routers
+	routes []addedRoute[HandlerFunc, MiddlewareFunc, Route]
+
+	// parent is the router a group or a host router was created from
+	parent *Router[HandlerFunc, MiddlewareFunc, Route]
+
+	// referencesLock serializes the resolution of the references of the schemas,
+	// which updates the shared components, with the validation of requests and
+	// responses reading them
+	referencesLock *sync.RWMutex
+
 	// hasSchema tracks whether this router has its own schema set
 	hasSchema bool
 }
@@ -161,6 +194,15 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) Group(pathPrefix string) (*
 		rootRouter:            r.rootRouter,                        // Reference the root router
 		hostRouters:           r.rootRouter.hostRouters,            // Share host routers map
 		reflectorOptions:      r.reflectorOptions,                  // Share reflector options
+		options:               r.options,                           // Share router options
+		authenticators:        r.authenticators,                    // Share the authenticators of the schema
+		enums:                 r.enums,                             // Share enum types
+		interfaces:            r.interfaces,                        // Share interface types
+		componentTypes:        r.componentTypes,                    // Share the component types of the schema
+		defaults:              r.defaults,                          // Inherit the defaults of the parent
+		inheritedDefaults:     r.defaults,
+		parent:                r,
+		referencesLock:        r.referencesLock,
 		isSubrouter:           true,
 	}, nil
 }
@@ -218,6 +260,13 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) Host(host string) (*Router[
 		rootRouter:            r,
 		hostRouters:           r.hostRouters,      // Share the host routers map
 		reflectorOptions:      r.reflectorOptions, // Share reflector options
+		options:               r.options,          // Share router options
+		authenticators:        make(map[string]Authenticator),
+		enums:                 r.enums,      // Share enum types
+		interfaces:            r.interfaces, // Share interface types
+		componentTypes:        newComponentTypes(),
+		parent:                r,
+		referencesLock:        r.referencesLock,
 	}
 
 	r.hostRouters[host] = hostRouter
@@ -250,6 +299,23 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) SetInfo(info *openapi3.Info
 	return r
 }
 
+// routerOptions holds the options shared by the root router with its groups
+// and host routers.
+type routerOptions struct {
+	validateRequests               bool
+	responseValidation             ResponseValidationMode
+	responseValidationErrorHandler func(req *http.Request, err error)
+	errorHandler                   func(req *http.Request, err error)
+	uiDocumentationPath            string
+	operationID                    OperationIDFunc
+	strict                         bool
+	warningHandler                 func(err error)
+	validatorTag                   string
+	typeMappings                   typeMappings
+	componentNamer                 ComponentNamer
+	skipExamplesValidation         bool
+}
+
 type Options[HandlerFunc any, MiddlewareFunc any, Route any] struct {
 	Context context.Context
 	Openapi *openapi3.T
@@ -257,6 +323,9 @@ type Options[HandlerFunc any, MiddlewareFunc any, Route any] struct {
 	JSONDocumentationPath string
 	// YAMLDocumentationPath is the path exposed by yaml endpoint. Default to /documentation/yaml.
 	YAMLDocumentationPath string
+	// UIDocumentationPath is the path exposing an interactive documentation UI,
+	// loading the json documentation. Disabled if empty.
+	UIDocumentationPath string
 	// Add path prefix to add to every router path.
 	PathPrefix string
 	// FrameworkRouterFactory is a function that creates a new instance of the underlying framework router.
@@ -267,6 +336,54 @@ type Options[HandlerFunc any, MiddlewareFunc any, Route any] struct {
 	CustomServeHTTPHandler http.Handler
 	// ReflectorOptions provides configuration for the jsonschema.Reflector used to generate schemas
 	ReflectorOptions *jsonschema.Reflector
+	// ValidateRequests enables runtime validation of parameters and request bodies
+	// against the operation registered for each route. Invalid requests are rejected
+	// with a 400 RequestValidationError. The framework router must implement
+	// apirouter.ValidatingRouter.
+	ValidateRequests bool
+	// ResponseValidation checks the status code, content type and body written by
+	// every handler against the documented responses. Meant for development and tests.
+	// The framework router must implement apirouter.ResponseValidatingRouter.
+	ResponseValidation ResponseValidationMode
+	// ResponseValidationErrorHandler is called with every invalid response.
+	// Default to log the error with the standard logger.
+	ResponseValidationErrorHandler func(req *http.Request, err error)
+	// ErrorHandler is called with the errors answered by typed routes with a
+	// generic 500 Internal Server Error, such as the errors returned by their
+	// handlers which do not implement apirouter.HTTPError, so that they are not
+	// sent to the client. Default to log them with the standard logger.
+	ErrorHandler func(req *http.Request, err error)
+	// OperationID returns the operationId of the routes defined without one,
+	// such as DefaultOperationID. The operationIds of a schema must be unique:
+	// duplicates are reported by GenerateAndExposeOpenapi.
+	OperationID OperationIDFunc
+	// Strict makes AddRoute fail when the schema of a parameter cannot be generated,
+	// with every failure joined to ErrPathParams, ErrQuerystring, ErrHeaders or ErrCookies.
+	// Otherwise such parameters are left out of the operation and reported to WarningHandler.
+	// It also makes GenerateAndExposeOpenapi fail with ErrSecurityScheme when a security
+	// requirement references an undefined scheme, which is otherwise reported to WarningHandler.
+	Strict bool
+	// WarningHandler is called with the problems which do not make route registration
+	// fail, such as dropped parameters. Default to log them with the standard logger.
+	WarningHandler func(err error)
+	// ValidatorTag is the name of the go-playground/validator struct tags, such as
+	// "validate", or "binding" with gin, translated into schema constraints:
+	// required, min, max, len, gt, gte, lt, lte, oneof, formats such as email
+	// and patterns such as alphanum. Disabled if empty.
+	ValidatorTag string
+	// TypeMappings maps Go types to the schema documenting them instead of the
+	// reflected one, such as types with a custom JSON encoding. They override the
+	// default mappings of well-known types, such as uuid.UUID, time.Duration and
+	// the sql.Null types. A nil schema restores the reflection of a type.
+	TypeMappings map[reflect.Type]*openapi3.Schema
+	// ComponentNamer names the component of a type whose name is already used by
+	// the component of another type, such as PackageQualifiedName. Without it, or
+	// if the new name is also used, AddRoute fails with ErrComponentNameCollision.
+	ComponentNamer ComponentNamer
+	// SkipExamplesValidation documents the examples without validating them
+	// against their schema. By default GenerateAndExposeOpenapi fails with
+	// ErrValidatingOAS on examples which do not match their schema.
+	SkipExamplesValidation bool
 }
 
 func NewRouter[HandlerFunc, MiddlewareFunc, Route any](frameworkRouter apirouter.Router[HandlerFunc, MiddlewareFunc, Route], options Options[HandlerFunc, MiddlewareFunc, Route]) (*Router[HandlerFunc, MiddlewareFunc, Route], error) {
@@ -296,6 +413,28 @@ func NewRouter[HandlerFunc, MiddlewareFunc, Route any](frameworkRouter apirouter
 		jsonDocumentationPath = options.JSONDocumentationPath
 	}
 
+	if options.UIDocumentationPath != "" {
+		if err := isValidDocumentationPath(options.UIDocumentationPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.ValidateRequests {
+		if _, ok := frameworkRouter.(apirouter.ValidatingRouter[MiddlewareFunc]); !ok {
+			return nil, ErrValidationNotSupported
+		}
+	}
+	if options.ResponseValidation != ResponseValidationDisabled {
+		if _, ok := frameworkRouter.(apirouter.ResponseValidatingRouter[MiddlewareFunc]); !ok {
+			return nil, ErrValidationNotSupported
+		}
+	}
+
+	typeMappings, err := newTypeMappings(options.TypeMappings)
+	if err != nil {
+		return nil, err
+	}
+
 	defaultFrameworkRouterWithPrefix := frameworkRouter
 	if options.PathPrefix != "" {
 		defaultFrameworkRouterWithPrefix = frameworkRouter.Group(options.PathPrefix)
@@ -314,6 +453,25 @@ func NewRouter[HandlerFunc, MiddlewareFunc, Route any](frameworkRouter apirouter
 		frameworkRouterFactory: options.FrameworkRouterFactory,
 		customServeHTTPHandler: options.CustomServeHTTPHandler,
 		reflectorOptions:       options.ReflectorOptions,
+		options: routerOptions{
+			validateRequests:               options.ValidateRequests,
+			responseValidation:             options.ResponseValidation,
+			responseValidationErrorHandler: options.ResponseValidationErrorHandler,
+			errorHandler:                   options.ErrorHandler,
+			uiDocumentationPath:            options.UIDocumentationPath,
+			operationID:                    options.OperationID,
+			strict:                         options.Strict,
+			warningHandler:                 options.WarningHandler,
+			validatorTag:                   options.ValidatorTag,
+			typeMappings:                   typeMappings,
+			componentNamer:                 options.ComponentNamer,
+			skipExamplesValidation:         options.SkipExamplesValidation,
+		},
+		authenticators: make(map[string]Authenticator),
+		enums:          make(map[reflect.Type]enumDefinition),
+		interfaces:     make(map[reflect.Type]*Polymorphic),
+		componentTypes: newComponentTypes(),
+		referencesLock: &sync.RWMutex{},
 	}
 	root.rootRouter = root
 
@@ -359,45 +517,35 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) ServeHTTP(w http.ResponseWr
 	}
 
 	// Handle swagger documentation requests
-	expectedJSONDocPath := targetRouter.jsonDocumentationPath
-	expectedYAMLDocPath := targetRouter.yamlDocumentationPath
-	if pp := targetRouter.pathPrefix; pp != "" {
-		expectedJSONDocPath = pp + expectedJSONDocPath
-		expectedYAMLDocPath = pp + expectedYAMLDocPath
+	docPaths := targetRouter.documentationPaths()
+	docIndex := -1
+	for i, docPath := range docPaths {
+		if req.URL.Path == targetRouter.pathPrefix+docPath {
+			docIndex = i
+			break
+		}
 	}
 
 	// Check if the current router or its host has a schema set
 	routerWithSchema := r.getRouterWithSchema(targetRouter)
 
-	if req.URL.Path == expectedJSONDocPath || req.URL.Path == expectedYAMLDocPath {
+	if docIndex >= 0 {
 		if routerWithSchema != nil {
 			if handler, ok := routerWithSchema.router.Router(true).(http.Handler); ok {
 				// If we're delegating to a different router, we need to adjust the request path
 				if routerWithSchema != targetRouter {
 					// Clone the request
 					clonedReq := req.Clone(req.Context())
-					
-					// Determine the path adjustment needed
-					// We need to convert from targetRouter's expected path to routerWithSchema's expected path
-					var adjustedPath string
-					if req.URL.Path == expectedJSONDocPath {
-						adjustedPath = routerWithSchema.jsonDocumentationPath
-						if pp := routerWithSchema.pathPrefix; pp != "" {
-							adjustedPath = pp + adjustedPath
-						}
-					} else {
-						adjustedPath = routerWithSchema.yamlDocumentationPath
-						if pp := routerWithSchema.pathPrefix; pp != "" {
-							adjustedPath = pp + adjustedPath
-						}
-					}
-					
+
+					// Convert from targetRouter's expected path to routerWithSchema's expected path
+					adjustedPath := routerWithSchema.pathPrefix + routerWithSchema.documentationPaths()[docIndex]
+
 					// Update the cloned request's URL
 					clonedReq.URL.Path = adjustedPath
 					if clonedReq.URL.RawPath != "" {
 						clonedReq.URL.RawPath = adjustedPath
 					}
-					
+
 					// Update RequestURI if present
 					if clonedReq.RequestURI != "" {
 						// Preserve query parameters if any
@@ -407,7 +555,7 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) ServeHTTP(w http.ResponseWr
 							clonedReq.RequestURI = adjustedPath
 						}
 					}
-					
+
 					handler.ServeHTTP(w, clonedReq)
 					return
 				} else {
@@ -488,6 +636,8 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) GenerateAndExposeOpenapi()
 	}
 
 	// Resolve all references in paths
+	r.referencesLock.Lock()
+	defer r.referencesLock.Unlock()
 	if r.swaggerSchema.Paths != nil {
 		for _path, pathItem := range r.swaggerSchema.Paths.Map() {
 			for method, operation := range pathItem.Operations() {
@@ -500,6 +650,19 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) GenerateAndExposeOpenapi()
 		}
 	}
 
+	if err := validateOperationIDs(r.swaggerSchema); err != nil {
+		return fmt.Errorf("%w for %s: %w", ErrGenerateOAS, routerType, err)
+	}
+
+	// Undefined security schemes are only rejected in strict mode, so that
+	// schemas generated before the schemes were checked keep working
+	if errs := validateSecurityRequirements(r.swaggerSchema); len(errs) > 0 {
+		if r.options.strict {
+			return fmt.Errorf("%w for %s: %w", ErrGenerateOAS, routerType, errors.Join(errs...))
+		}
+		r.warn(errs...)
+	}
+
 	// Resolve all reusable components after paths are processed
 	if err := ResolveAllComponents(r.swaggerSchema); err != nil {
 		return fmt.Errorf("%w: failed to resolve components: %v", ErrGenerateOAS, err)
@@ -512,7 +675,11 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) GenerateAndExposeOpenapi()
 	}
 
 	// Validate the schema
-	if err := r.swaggerSchema.Validate(r.context); err != nil {
+	validationOpts := validationOptions(r.swaggerSchema)
+	if r.options.skipExamplesValidation {
+		validationOpts = append(validationOpts, openapi3.DisableExamplesValidation())
+	}
+	if err := r.swaggerSchema.Validate(r.context, validationOpts...); err != nil {
 		return fmt.Errorf("%w for %s: %s", ErrValidatingOAS, routerType, err)
 	}
 
@@ -522,6 +689,14 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) GenerateAndExposeOpenapi()
 		return fmt.Errorf("%w json marshal for %s: %s", ErrGenerateOAS, routerType, err)
 	}
 
+	// Convert the schemas to JSON Schema 2020-12 in 3.1 output mode
+	if isOpenapi31(r.swaggerSchema) {
+		jsonSwagger, err = convertToOpenapi31(jsonSwagger)
+		if err != nil {
+			return fmt.Errorf("%w json marshal for %s: %s", ErrGenerateOAS, routerType, err)
+		}
+	}
+
 	// Add JSON documentation route
 	jsonPath := r.jsonDocumentationPath
 	// The pathPrefix is already applied to the underlying router in NewRouter,
@@ -538,6 +713,13 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) GenerateAndExposeOpenapi()
 	// so we register the documentation handler with the path *including* the prefix.
 	r.router.AddRoute(http.MethodGet, yamlPath, r.router.SwaggerHandler("text/plain", yamlSwagger))
 
+	// Add documentation UI routes
+	if r.options.uiDocumentationPath != "" {
+		if err := r.exposeDocumentationUI(); err != nil {
+			return fmt.Errorf("%w ui for %s: %s", ErrGenerateOAS, routerType, err)
+		}
+	}
+
 	return nil
 }
 
@@ -567,6 +749,13 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) getRouterWithSchema(targetR
 	return nil
 }
 
+// documentationPaths returns the paths of the documentation exposed by the
+// router, without the path prefix: json, yaml, then the UI paths if enabled.
+// Routers sharing the same options return the paths in the same order.
+func (r *Router[HandlerFunc, MiddlewareFunc, Route]) documentationPaths() []string {
+	return append([]string{r.jsonDocumentationPath, r.yamlDocumentationPath}, r.uiDocumentationPaths()...)
+}
+
 func isValidDocumentationPath(path string) error {
 	if !strings.HasPrefix(path, "/") {
 		return fmt.Errorf("invalid path %s. Path should start with '/'", path)
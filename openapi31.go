@@ -0,0 +1,286 @@
+package swagger
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// OpenapiVersion31 is the OpenAPI version enabling the 3.1 output mode.
+// Set it as the OpenAPI field of Options.Openapi to expose JSON Schema 2020-12
+// compatible schemas.
+const OpenapiVersion31 = "3.1.0"
+
+// jsonSchema31Keywords are the JSON Schema 2020-12 keywords kept on the
+// generated schemas in 3.1 output mode.
+var jsonSchema31Keywords = []string{"examples", "const", "$defs"}
+
+// isOpenapi31 reports whether the document uses the 3.1 output mode.
+func isOpenapi31(openapi *openapi3.T) bool {
+	return openapi != nil && strings.HasPrefix(openapi.OpenAPI, "3.1")
+}
+
+// validationOptions returns the options used to validate the document.
+func validationOptions(openapi *openapi3.T) []openapi3.ValidationOption {
+	if !isOpenapi31(openapi) {
+		return nil
+	}
+	return []openapi3.ValidationOption{
+		openapi3.AllowExtraSiblingFields(jsonSchema31Keywords...),
+	}
+}
+
+// normalizeReflectedSchema converts a schema generated by the jsonschema
+// reflector to the shape stored in the document: the 3.0 shape for nullable
+// types and exclusive bounds, which is converted back by convertToOpenapi31.
+// In 3.0 mode examples and const, not supported by OpenAPI 3.0, are converted
+// to example and enum.
+func normalizeReflectedSchema(data []byte, openapi31 bool) ([]byte, error) {
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	walkSchema(schema, func(schema map[string]any) {
+		normalizeSchemaObject(schema, openapi31)
+	})
+	return json.Marshal(schema)
+}
+
+func normalizeSchemaObject(schema map[string]any, openapi31 bool) {
+	applyTypeDescription(schema)
+
+	// type: [T, "null"] is stored as type T with nullable
+	if types, ok := schema["type"].([]any); ok {
+		var notNull []any
+		for _, t := range types {
+			if t == openapi3.TypeNull {
+				schema["nullable"] = true
+			} else {
+				notNull = append(notNull, t)
+			}
+		}
+		if len(notNull) == 1 {
+			schema["type"] = notNull[0]
+		} else {
+			schema["type"] = notNull
+		}
+	}
+
+	// oneOf: [S, {type: "null"}] is stored as S with nullable
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		variants, ok := schema[keyword].([]any)
+		if !ok || len(variants) != 2 {
+			continue
+		}
+		for i, variant := range variants {
+			other, ok := variants[1-i].(map[string]any)
+			if !isNullSchema(variant) || !ok || other["$ref"] != nil {
+				continue
+			}
+			delete(schema, keyword)
+			for k, v := range other {
+				schema[k] = v
+			}
+			schema["nullable"] = true
+			break
+		}
+	}
+
+	// Numeric exclusive bounds are stored as boolean flags
+	for exclusive, bound := range map[string]string{"exclusiveMinimum": "minimum", "exclusiveMaximum": "maximum"} {
+		if value, ok := schema[exclusive].(float64); ok {
+			schema[bound] = value
+			schema[exclusive] = true
+		}
+	}
+
+	if openapi31 {
+		return
+	}
+	if examples, ok := schema["examples"].([]any); ok {
+		if len(examples) > 0 {
+			schema["example"] = examples[0]
+		}
+		delete(schema, "examples")
+	}
+	if value, ok := schema["const"]; ok {
+		schema["enum"] = []any{value}
+		delete(schema, "const")
+	}
+}
+
+func isNullSchema(schema any) bool {
+	s, ok := schema.(map[string]any)
+	return ok && len(s) == 1 && s["type"] == openapi3.TypeNull
+}
+
+// convertToOpenapi31 converts the schemas of a JSON document generated from
+// the 3.0 shaped openapi3.T to JSON Schema 2020-12:
+//   - nullable becomes a "null" type;
+//   - example becomes examples;
+//   - boolean exclusiveMinimum and exclusiveMaximum become numeric bounds.
+func convertToOpenapi31(data []byte) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	walkDocumentSchemas(doc, func(schema map[string]any) {
+		walkSchema(schema, convertSchemaObject)
+	})
+	return json.Marshal(doc)
+}
+
+func convertSchemaObject(schema map[string]any) {
+	if nullable, ok := schema["nullable"].(bool); ok {
+		delete(schema, "nullable")
+		if nullable {
+			switch t := schema["type"].(type) {
+			case string:
+				schema["type"] = []any{t, openapi3.TypeNull}
+			case []any:
+				schema["type"] = append(t, openapi3.TypeNull)
+			default:
+				variant := make(map[string]any, len(schema))
+				for k, v := range schema {
+					variant[k] = v
+					delete(schema, k)
+				}
+				schema["anyOf"] = []any{variant, map[string]any{"type": openapi3.TypeNull}}
+			}
+		}
+	}
+
+	if example, ok := schema["example"]; ok {
+		if _, exists := schema["examples"]; !exists {
+			schema["examples"] = []any{example}
+		}
+		delete(schema, "example")
+	}
+
+	for exclusive, bound := range map[string]string{"exclusiveMinimum": "minimum", "exclusiveMaximum": "maximum"} {
+		isExclusive, ok := schema[exclusive].(bool)
+		if !ok {
+			continue
+		}
+		delete(schema, exclusive)
+		if value, ok := schema[bound]; ok && isExclusive {
+			schema[exclusive] = value
+			delete(schema, bound)
+		}
+	}
+}
+
+// walkSchema calls fn on schema and on all its subschemas.
+func walkSchema(schema map[string]any, fn func(schema map[string]any)) {
+	fn(schema)
+
+	for _, keyword := range []string{"items", "not", "additionalProperties"} {
+		if subschema, ok := schema[keyword].(map[string]any); ok {
+			walkSchema(subschema, fn)
+		}
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf", "prefixItems"} {
+		for _, subschema := range asSlice(schema[keyword]) {
+			walkSchema(subschema, fn)
+		}
+	}
+	for _, keyword := range []string{"properties", "patternProperties", "$defs", "definitions"} {
+		for _, subschema := range asMap(schema[keyword]) {
+			walkSchema(subschema, fn)
+		}
+	}
+}
+
+// walkDocumentSchemas calls fn on every top level schema of an OpenAPI document.
+func walkDocumentSchemas(doc map[string]any, fn func(schema map[string]any)) {
+	components := asObject(doc["components"])
+	for _, schema := range asMap(components["schemas"]) {
+		fn(schema)
+	}
+	for _, parameter := range asMap(components["parameters"]) {
+		walkParameterSchemas(parameter, fn)
+	}
+	for _, header := range asMap(components["headers"]) {
+		walkParameterSchemas(header, fn)
+	}
+	for _, requestBody := range asMap(components["requestBodies"]) {
+		walkContentSchemas(requestBody, fn)
+	}
+	for _, response := range asMap(components["responses"]) {
+		walkResponseSchemas(response, fn)
+	}
+
+	for _, pathItem := range asMap(doc["paths"]) {
+		for key, value := range pathItem {
+			if key == "parameters" {
+				for _, parameter := range asSlice(value) {
+					walkParameterSchemas(parameter, fn)
+				}
+				continue
+			}
+			operation, ok := value.(map[string]any)
+			if !ok {
+				continue
+			}
+			for _, parameter := range asSlice(operation["parameters"]) {
+				walkParameterSchemas(parameter, fn)
+			}
+			walkContentSchemas(asObject(operation["requestBody"]), fn)
+			for _, response := range asMap(operation["responses"]) {
+				walkResponseSchemas(response, fn)
+			}
+		}
+	}
+}
+
+func walkParameterSchemas(parameter map[string]any, fn func(schema map[string]any)) {
+	if schema, ok := parameter["schema"].(map[string]any); ok {
+		fn(schema)
+	}
+	walkContentSchemas(parameter, fn)
+}
+
+func walkResponseSchemas(response map[string]any, fn func(schema map[string]any)) {
+	walkContentSchemas(response, fn)
+	for _, header := range asMap(response["headers"]) {
+		walkParameterSchemas(header, fn)
+	}
+}
+
+func walkContentSchemas(value map[string]any, fn func(schema map[string]any)) {
+	for _, mediaType := range asMap(value["content"]) {
+		if schema, ok := mediaType["schema"].(map[string]any); ok {
+			fn(schema)
+		}
+	}
+}
+
+func asObject(value any) map[string]any {
+	object, _ := value.(map[string]any)
+	return object
+}
+
+// asMap returns the object values of a JSON object.
+func asMap(value any) map[string]map[string]any {
+	result := map[string]map[string]any{}
+	for k, v := range asObject(value) {
+		if object, ok := v.(map[string]any); ok {
+			result[k] = object
+		}
+	}
+	return result
+}
+
+// asSlice returns the object items of a JSON array.
+func asSlice(value any) []map[string]any {
+	items, _ := value.([]any)
+	result := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if object, ok := item.(map[string]any); ok {
+			result = append(result, object)
+		}
+	}
+	return result
+}
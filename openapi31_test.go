@@ -0,0 +1,139 @@
+package swagger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.lumeweb.com/gswagger/support/gorilla"
+	"go.lumeweb.com/gswagger/support/testutils"
+)
+
+func TestOpenapi31(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type User struct {
+		Name    *string  `json:"name" jsonschema:"nullable,example=Jane,example=John"`
+		Age     int      `json:"age" jsonschema:"exclusiveMinimum=0,exclusiveMaximum=150"`
+		Address *Address `json:"address,omitempty"`
+	}
+
+	setup := func(t *testing.T, version string) *mux.Router {
+		t.Helper()
+
+		muxRouter := mux.NewRouter()
+		openapi := getBaseSwagger(t)
+		openapi.OpenAPI = version
+		router, err := NewRouter(gorilla.NewRouter(muxRouter), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Context: context.Background(),
+			Openapi: openapi,
+		})
+		require.NoError(t, err)
+
+		_, err = router.AddRoute(http.MethodPost, "/users", okHandler, Definitions{
+			Querystring: ParameterValue{
+				"limit": {Schema: &Schema{Value: 0}},
+			},
+			RequestBody: &ContentValue{
+				Content: Content{
+					jsonType: {Value: User{}},
+				},
+			},
+			Responses: map[int]ContentValue{
+				201: {
+					Content: Content{
+						jsonType: {Value: User{}},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		return muxRouter
+	}
+
+	getDocumentation := func(t *testing.T, muxRouter *mux.Router, path string) string {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		muxRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		return readBody(t, w.Result().Body)
+	}
+
+	t.Run("3.1 output emits JSON Schema 2020-12", func(t *testing.T) {
+		muxRouter := setup(t, OpenapiVersion31)
+
+		body := getDocumentation(t, muxRouter, DefaultJSONDocumentationPath)
+		testutils.AssertJSONMatchesFile(t, []byte(body), "testdata/openapi31.json")
+
+		yamlBody := getDocumentation(t, muxRouter, DefaultYAMLDocumentationPath)
+		require.Contains(t, yamlBody, "openapi: 3.1.0")
+		require.NotContains(t, yamlBody, "nullable")
+	})
+
+	t.Run("3.0 output converts 3.1 keywords", func(t *testing.T) {
+		muxRouter := setup(t, "")
+
+		body := getDocumentation(t, muxRouter, DefaultJSONDocumentationPath)
+		testutils.AssertJSONMatchesFile(t, []byte(body), "testdata/openapi30-keywords.json")
+	})
+}
+
+func TestConvertToOpenapi31(t *testing.T) {
+	converted, err := convertToOpenapi31([]byte(`{
+		"openapi": "3.1.0",
+		"components": {
+			"schemas": {
+				"Nullable": {"type": "string", "nullable": true, "example": "foo"},
+				"NullableRef": {"allOf": [{"$ref": "#/components/schemas/Other"}], "nullable": true},
+				"NotNullable": {"type": "string", "nullable": false},
+				"Bounds": {"type": "number", "minimum": 1, "exclusiveMinimum": true, "maximum": 5, "exclusiveMaximum": false}
+			}
+		},
+		"paths": {
+			"/items": {
+				"get": {
+					"parameters": [{"name": "q", "in": "query", "example": "foo", "schema": {"type": "string", "nullable": true}}],
+					"responses": {
+						"200": {
+							"description": "",
+							"content": {"application/json": {"example": {"id": 1}, "schema": {"type": "array", "items": {"type": "integer", "nullable": true}}}}
+						}
+					}
+				}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+
+	require.JSONEq(t, `{
+		"openapi": "3.1.0",
+		"components": {
+			"schemas": {
+				"Nullable": {"type": ["string", "null"], "examples": ["foo"]},
+				"NullableRef": {"anyOf": [{"allOf": [{"$ref": "#/components/schemas/Other"}]}, {"type": "null"}]},
+				"NotNullable": {"type": "string"},
+				"Bounds": {"type": "number", "exclusiveMinimum": 1, "maximum": 5}
+			}
+		},
+		"paths": {
+			"/items": {
+				"get": {
+					"parameters": [{"name": "q", "in": "query", "example": "foo", "schema": {"type": ["string", "null"]}}],
+					"responses": {
+						"200": {
+							"description": "",
+							"content": {"application/json": {"example": {"id": 1}, "schema": {"type": "array", "items": {"type": ["integer", "null"]}}}}
+						}
+					}
+				}
+			}
+		}
+	}`, string(converted))
+}
@@ -417,9 +417,16 @@ func convertSchemaRefToStandardFormat(schemaRef *openapi3.SchemaRef, context, me
 
 // processSchemaRefs recursively processes all references within a schema
 func processSchemaRefs(rootSchema *openapi3.T, schema *openapi3.Schema) error {
-	if schema == nil {
+	return processSchemaRefsOnce(rootSchema, schema, map[*openapi3.Schema]bool{})
+}
+
+// processSchemaRefsOnce processes the references within a schema, skipping
+// the schemas already visited, so that recursive components terminate.
+func processSchemaRefsOnce(rootSchema *openapi3.T, schema *openapi3.Schema, visited map[*openapi3.Schema]bool) error {
+	if schema == nil || visited[schema] {
 		return nil
 	}
+	visited[schema] = true
 
 	// Process properties
 	for _, prop := range schema.Properties {
@@ -427,14 +434,14 @@ func processSchemaRefs(rootSchema *openapi3.T, schema *openapi3.Schema) error {
 			if err := convertSchemaRefToStandardFormat(prop, "schema property", ""); err != nil {
 				return err
 			}
-			resolved, err := resolveComponentRef(rootSchema, prop.Ref)
+			resolved, err := resolveComponentRefOnce(rootSchema, prop.Ref, visited)
 			if err != nil {
 				return fmt.Errorf("schema property %q: %w", prop.Ref, err)
 			}
 			prop.Value = resolved.(*openapi3.Schema)
 		}
 		if prop.Value != nil {
-			if err := processSchemaRefs(rootSchema, prop.Value); err != nil {
+			if err := processSchemaRefsOnce(rootSchema, prop.Value, visited); err != nil {
 				return err
 			}
 		}
@@ -446,14 +453,14 @@ func processSchemaRefs(rootSchema *openapi3.T, schema *openapi3.Schema) error {
 			if err := convertSchemaRefToStandardFormat(schema.Items, "schema items", ""); err != nil {
 				return err
 			}
-			resolved, err := resolveComponentRef(rootSchema, schema.Items.Ref)
+			resolved, err := resolveComponentRefOnce(rootSchema, schema.Items.Ref, visited)
 			if err != nil {
 				return fmt.Errorf("schema items: %w", err)
 			}
 			schema.Items.Value = resolved.(*openapi3.Schema)
 		}
 		if schema.Items.Value != nil {
-			if err := processSchemaRefs(rootSchema, schema.Items.Value); err != nil {
+			if err := processSchemaRefsOnce(rootSchema, schema.Items.Value, visited); err != nil {
 				return err
 			}
 		}
@@ -465,14 +472,14 @@ func processSchemaRefs(rootSchema *openapi3.T, schema *openapi3.Schema) error {
 			if err := convertSchemaRefToStandardFormat(schema.AdditionalProperties.Schema, "schema additionalProperties", ""); err != nil {
 				return err
 			}
-			resolved, err := resolveComponentRef(rootSchema, schema.AdditionalProperties.Schema.Ref)
+			resolved, err := resolveComponentRefOnce(rootSchema, schema.AdditionalProperties.Schema.Ref, visited)
 			if err != nil {
 				return fmt.Errorf("schema additionalProperties: %w", err)
 			}
 			schema.AdditionalProperties.Schema.Value = resolved.(*openapi3.Schema)
 		}
 		if schema.AdditionalProperties.Schema.Value != nil {
-			if err := processSchemaRefs(rootSchema, schema.AdditionalProperties.Schema.Value); err != nil {
+			if err := processSchemaRefsOnce(rootSchema, schema.AdditionalProperties.Schema.Value, visited); err != nil {
 				return err
 			}
 		}
@@ -484,14 +491,14 @@ func processSchemaRefs(rootSchema *openapi3.T, schema *openapi3.Schema) error {
 			if err := convertSchemaRefToStandardFormat(s, "schema allOf", ""); err != nil {
 				return err
 			}
-			resolved, err := resolveComponentRef(rootSchema, s.Ref)
+			resolved, err := resolveComponentRefOnce(rootSchema, s.Ref, visited)
 			if err != nil {
 				return fmt.Errorf("schema allOf[%d]: %w", i, err)
 			}
 			s.Value = resolved.(*openapi3.Schema)
 		}
 		if s.Value != nil {
-			if err := processSchemaRefs(rootSchema, s.Value); err != nil {
+			if err := processSchemaRefsOnce(rootSchema, s.Value, visited); err != nil {
 				return err
 			}
 		}
@@ -501,14 +508,14 @@ func processSchemaRefs(rootSchema *openapi3.T, schema *openapi3.Schema) error {
 			if err := convertSchemaRefToStandardFormat(s, "schema anyOf", ""); err != nil {
 				return err
 			}
-			resolved, err := resolveComponentRef(rootSchema, s.Ref)
+			resolved, err := resolveComponentRefOnce(rootSchema, s.Ref, visited)
 			if err != nil {
 				return fmt.Errorf("schema anyOf[%d]: %w", i, err)
 			}
 			s.Value = resolved.(*openapi3.Schema)
 		}
 		if s.Value != nil {
-			if err := processSchemaRefs(rootSchema, s.Value); err != nil {
+			if err := processSchemaRefsOnce(rootSchema, s.Value, visited); err != nil {
 				return err
 			}
 		}
@@ -518,14 +525,14 @@ func processSchemaRefs(rootSchema *openapi3.T, schema *openapi3.Schema) error {
 			if err := convertSchemaRefToStandardFormat(s, "schema oneOf", ""); err != nil {
 				return err
 			}
-			resolved, err := resolveComponentRef(rootSchema, s.Ref)
+			resolved, err := resolveComponentRefOnce(rootSchema, s.Ref, visited)
 			if err != nil {
 				return fmt.Errorf("schema oneOf[%d]: %w", i, err)
 			}
 			s.Value = resolved.(*openapi3.Schema)
 		}
 		if s.Value != nil {
-			if err := processSchemaRefs(rootSchema, s.Value); err != nil {
+			if err := processSchemaRefsOnce(rootSchema, s.Value, visited); err != nil {
 				return err
 			}
 		}
@@ -535,6 +542,12 @@ func processSchemaRefs(rootSchema *openapi3.T, schema *openapi3.Schema) error {
 }
 
 func resolveComponentRef(rootSchema *openapi3.T, ref string) (any, error) {
+	return resolveComponentRefOnce(rootSchema, ref, map[*openapi3.Schema]bool{})
+}
+
+// resolveComponentRefOnce resolves a reference, processing the references of
+// the schemas not already visited.
+func resolveComponentRefOnce(rootSchema *openapi3.T, ref string, visited map[*openapi3.Schema]bool) (any, error) {
 	if !strings.HasPrefix(ref, "#/") {
 		return nil, fmt.Errorf("reference %q must point to a component", ref)
 	}
@@ -580,7 +593,7 @@ func resolveComponentRef(rootSchema *openapi3.T, ref string) (any, error) {
 
 		// Recursively resolve references within the schema itself
 		if schema.Value != nil {
-			if err := processSchemaRefs(rootSchema, schema.Value); err != nil {
+			if err := processSchemaRefsOnce(rootSchema, schema.Value, visited); err != nil {
 				return nil, fmt.Errorf("failed to process schema refs for %q: %w", componentName, err)
 			}
 		}
@@ -0,0 +1,163 @@
+package swagger
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ErrOperationID is returned by GenerateAndExposeOpenapi when two operations
+// of the same schema have the same operationId.
+var ErrOperationID = errors.New("duplicate operationId")
+
+// OperationIDFunc returns the default operationId of a route.
+// oasPath is the OpenAPI path of the route, including pathPrefix,
+// which is the path prefix of the router the route is added to.
+// An empty string leaves the operationId unset.
+type OperationIDFunc func(method, oasPath, pathPrefix string) string
+
+// operationIDVerbs maps HTTP methods to the verb starting the default operationId.
+var operationIDVerbs = map[string]string{
+	http.MethodGet:    "get",
+	http.MethodPost:   "create",
+	http.MethodPut:    "update",
+	http.MethodPatch:  "patch",
+	http.MethodDelete: "delete",
+}
+
+// DefaultOperationID derives a camelCase operationId from the method and the
+// path of a route: POST /users gives createUser, GET /users/{id} gives
+// getUserById and GET /users/{id}/posts gives getUserPosts. The segments of
+// the path prefix, except its parameters, are kept as they are, so that
+// GET /users in the /v1 and /v2 groups gives getV1Users and getV2Users,
+// while POST / in the /users group gives createUser.
+func DefaultOperationID(method, oasPath, pathPrefix string) string {
+	var prefix []string
+	pathPrefix = strings.TrimSuffix(pathPrefix, "/")
+	if oasPath == pathPrefix || strings.HasPrefix(oasPath, pathPrefix+"/") {
+		oasPath = strings.TrimPrefix(oasPath, pathPrefix)
+		for _, segment := range strings.Split(pathPrefix, "/") {
+			if segment == "" || isPathParam(segment) {
+				continue
+			}
+			prefix = append(prefix, pascalCase(segment))
+		}
+	}
+
+	verb, ok := operationIDVerbs[method]
+	if !ok {
+		verb = strings.ToLower(method)
+	}
+
+	var resources, params []string
+	for _, segment := range strings.Split(oasPath, "/") {
+		if segment == "" {
+			continue
+		}
+		if isPathParam(segment) {
+			// A param identifies a single resource of the collection before it
+			if len(params) == 0 && len(resources) > 0 {
+				resources[len(resources)-1] = singular(resources[len(resources)-1])
+			}
+			params = append(params, pascalCase(strings.Trim(segment, "{}")))
+			continue
+		}
+		resources = append(resources, pascalCase(segment))
+		params = nil
+	}
+
+	if len(resources) == 0 && len(prefix) == 0 {
+		resources = []string{"Root"}
+	}
+	if method == http.MethodPost && len(params) == 0 {
+		// POST to the root of a group creates a resource of its collection
+		if len(resources) == 0 {
+			prefix[len(prefix)-1] = singular(prefix[len(prefix)-1])
+		} else {
+			resources[len(resources)-1] = singular(resources[len(resources)-1])
+		}
+	}
+
+	operationID := verb + strings.Join(prefix, "") + strings.Join(resources, "")
+	if len(params) > 0 {
+		operationID += "By" + strings.Join(params, "And")
+	}
+	return operationID
+}
+
+// pascalCase converts a path segment such as user-groups to UserGroups.
+func pascalCase(segment string) string {
+	words := strings.FieldsFunc(segment, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, "")
+}
+
+// singular naively returns the singular of an English plural noun.
+func singular(noun string) string {
+	switch {
+	case strings.HasSuffix(noun, "ies") && len(noun) > 3:
+		return strings.TrimSuffix(noun, "ies") + "y"
+	case strings.HasSuffix(noun, "ss"), !strings.HasSuffix(noun, "s"), len(noun) < 2:
+		return noun
+	}
+	return strings.TrimSuffix(noun, "s")
+}
+
+// isPathParam reports whether the segment of an OpenAPI path is a parameter.
+func isPathParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// setOperationID sets the operationId of operation, if not already set,
+// using the OperationIDFunc of the router options.
+func (r *Router[_, _, _]) setOperationID(method, oasPath string, operation *openapi3.Operation) {
+	if operation.OperationID != "" || r.options.operationID == nil {
+		return
+	}
+	operation.OperationID = r.options.operationID(method, oasPath, r.router.TransformPathToOasPath(r.pathPrefix))
+}
+
+// validateOperationIDs checks that the operationIds of the schema are unique.
+func validateOperationIDs(schema *openapi3.T) error {
+	if schema.Paths == nil {
+		return nil
+	}
+
+	var errs []error
+	endpoints := make(map[string]string)
+	paths := schema.Paths.InMatchingOrder()
+	sort.Strings(paths)
+	for _, oasPath := range paths {
+		operations := schema.Paths.Value(oasPath).Operations()
+		methods := make([]string, 0, len(operations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			operationID := operations[method].OperationID
+			if operationID == "" {
+				continue
+			}
+			endpoint := method + " " + oasPath
+			if duplicate, ok := endpoints[operationID]; ok {
+				errs = append(errs, fmt.Errorf("%w %q for %s and %s", ErrOperationID, operationID, duplicate, endpoint))
+				continue
+			}
+			endpoints[operationID] = endpoint
+		}
+	}
+	return errors.Join(errs...)
+}
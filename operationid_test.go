@@ -0,0 +1,148 @@
+package swagger
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.lumeweb.com/gswagger/support/gorilla"
+)
+
+func TestDefaultOperationID(t *testing.T) {
+	testCases := []struct {
+		method     string
+		path       string
+		pathPrefix string
+		expected   string
+	}{
+		{method: http.MethodPost, path: "/users", expected: "createUser"},
+		{method: http.MethodGet, path: "/users", expected: "getUsers"},
+		{method: http.MethodGet, path: "/users/{id}", expected: "getUserById"},
+		{method: http.MethodPut, path: "/users/{id}", expected: "updateUserById"},
+		{method: http.MethodPatch, path: "/users/{id}", expected: "patchUserById"},
+		{method: http.MethodDelete, path: "/users/{id}", expected: "deleteUserById"},
+		{method: http.MethodGet, path: "/users/{id}/posts", expected: "getUserPosts"},
+		{method: http.MethodPost, path: "/users/{id}/posts", expected: "createUserPost"},
+		{method: http.MethodGet, path: "/users/{userId}/posts/{postId}", expected: "getUserPostByPostId"},
+		{method: http.MethodGet, path: "/orgs/{org}/{repo}", expected: "getOrgByOrgAndRepo"},
+		{method: http.MethodGet, path: "/user-groups/{group_id}", expected: "getUserGroupByGroupId"},
+		{method: http.MethodGet, path: "/categories/{id}", expected: "getCategoryById"},
+		{method: http.MethodGet, path: "/status", expected: "getStatus"},
+		{method: http.MethodGet, path: "/", expected: "getRoot"},
+		{method: http.MethodHead, path: "/users", expected: "headUsers"},
+		{method: http.MethodGet, path: "/api/v1/users/{id}", pathPrefix: "/api/v1", expected: "getApiV1UserById"},
+		{method: http.MethodGet, path: "/api/v1", pathPrefix: "/api/v1/", expected: "getApiV1"},
+		{method: http.MethodPost, path: "/v2/users", pathPrefix: "/v2", expected: "createV2User"},
+		{method: http.MethodGet, path: "/orgs/{org}/users", pathPrefix: "/orgs/{org}", expected: "getOrgsUsers"},
+		{method: http.MethodPost, path: "/users", pathPrefix: "/users", expected: "createUser"},
+		{method: http.MethodPost, path: "/users/", pathPrefix: "/users/", expected: "createUser"},
+		{method: http.MethodGet, path: "/users", pathPrefix: "/users", expected: "getUsers"},
+		{method: http.MethodGet, path: "/apiary", pathPrefix: "/api", expected: "getApiary"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.method+" "+test.path, func(t *testing.T) {
+			require.Equal(t, test.expected, DefaultOperationID(test.method, test.path, test.pathPrefix))
+		})
+	}
+}
+
+func TestOperationID(t *testing.T) {
+	setup := func(t *testing.T, operationID OperationIDFunc) *TestRouter {
+		t.Helper()
+
+		router, err := NewRouter(gorilla.NewRouter(mux.NewRouter()), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Context:     context.Background(),
+			Openapi:     getBaseSwagger(t),
+			OperationID: operationID,
+		})
+		require.NoError(t, err)
+		return router
+	}
+
+	t.Run("set from Definitions", func(t *testing.T) {
+		router := setup(t, nil)
+
+		_, err := router.AddRoute(http.MethodGet, "/users", okHandler, Definitions{OperationID: "listUsers"})
+		require.NoError(t, err)
+		_, err = router.AddRoute(http.MethodPost, "/users", okHandler, Definitions{})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		users := router.GetSwaggerSchema().Paths.Value("/users")
+		require.Equal(t, "listUsers", users.Get.OperationID)
+		require.Empty(t, users.Post.OperationID)
+	})
+
+	t.Run("default from the options, with the group prefix", func(t *testing.T) {
+		router := setup(t, DefaultOperationID)
+
+		group, err := router.Group("/api")
+		require.NoError(t, err)
+		_, err = group.AddRoute(http.MethodGet, "/users/{id}", okHandler, Definitions{})
+		require.NoError(t, err)
+		_, err = group.AddRoute(http.MethodGet, "/users", okHandler, Definitions{OperationID: "listUsers"})
+		require.NoError(t, err)
+		_, err = router.AddRawRoute(http.MethodPost, "/users", okHandler, Operation{})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		paths := router.GetSwaggerSchema().Paths
+		require.Equal(t, "getApiUserById", paths.Value("/api/users/{id}").Get.OperationID)
+		require.Equal(t, "listUsers", paths.Value("/api/users").Get.OperationID)
+		require.Equal(t, "createUser", paths.Value("/users").Post.OperationID)
+	})
+
+	t.Run("versioned groups", func(t *testing.T) {
+		router := setup(t, DefaultOperationID)
+
+		for _, version := range []string{"/v1", "/v2"} {
+			group, err := router.Group(version)
+			require.NoError(t, err)
+			_, err = group.AddRoute(http.MethodGet, "/users", okHandler, Definitions{})
+			require.NoError(t, err)
+		}
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		paths := router.GetSwaggerSchema().Paths
+		require.Equal(t, "getV1Users", paths.Value("/v1/users").Get.OperationID)
+		require.Equal(t, "getV2Users", paths.Value("/v2/users").Get.OperationID)
+	})
+
+	t.Run("group root", func(t *testing.T) {
+		router := setup(t, DefaultOperationID)
+
+		group, err := router.Group("/users")
+		require.NoError(t, err)
+		_, err = group.AddRoute(http.MethodPost, "/", okHandler, Definitions{})
+		require.NoError(t, err)
+		_, err = group.AddRoute(http.MethodGet, "/", okHandler, Definitions{})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		users := router.GetSwaggerSchema().Paths.Value("/users")
+		require.Equal(t, "createUser", users.Post.OperationID)
+		require.Equal(t, "getUsers", users.Get.OperationID)
+	})
+
+	t.Run("duplicates are reported", func(t *testing.T) {
+		router := setup(t, DefaultOperationID)
+
+		group, err := router.Group("/v2")
+		require.NoError(t, err)
+		_, err = router.AddRoute(http.MethodGet, "/users/{id}", okHandler, Definitions{})
+		require.NoError(t, err)
+		_, err = group.AddRoute(http.MethodGet, "/users/{id}", okHandler, Definitions{OperationID: "getUserById"})
+		require.NoError(t, err)
+		_, err = router.AddRoute(http.MethodPost, "/users", okHandler, Definitions{OperationID: "getUserById"})
+		require.NoError(t, err)
+
+		err = router.GenerateAndExposeOpenapi()
+		require.ErrorIs(t, err, ErrGenerateOAS)
+		require.ErrorIs(t, err, ErrOperationID)
+		require.EqualError(t, err, `fail to generate openapi for root: duplicate operationId "getUserById" for POST /users and GET /users/{id}
+duplicate operationId "getUserById" for POST /users and GET /v2/users/{id}`)
+	})
+}
@@ -0,0 +1,77 @@
+package swagger
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrQueryStruct indicates a Definitions.QueryStruct which can not describe parameters.
+var ErrQueryStruct = errors.New("invalid query struct")
+
+// parameterTags maps the struct tags naming parameters to their location.
+var parameterTags = []struct {
+	tag string
+	in  string
+}{
+	{pathTag, pathParamsType},
+	{queryTag, queryParamType},
+	{headerTag, headerParamType},
+	{cookieTag, cookieParamType},
+}
+
+// withStructParameters merges the parameters described by schema.QueryStruct
+// into schema, including the tagged fields promoted from embedded structs.
+// Parameters already present in schema are preserved.
+func withStructParameters(schema Definitions) (Definitions, error) {
+	if schema.QueryStruct == nil {
+		return schema, nil
+	}
+
+	t := reflect.TypeOf(schema.QueryStruct)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return schema, fmt.Errorf("%w: expected a struct, got %T", ErrQueryStruct, schema.QueryStruct)
+	}
+
+	params := map[string]ParameterValue{
+		pathParamsType:  copyParameterValue(schema.PathParams),
+		queryParamType:  copyParameterValue(schema.Querystring),
+		headerParamType: copyParameterValue(schema.Headers),
+		cookieParamType: copyParameterValue(schema.Cookies),
+	}
+	// Without a tag name, the fields are promoted from the embedded structs
+	// following the Go rules, whatever their other tags
+	for _, promoted := range promotedFields(t, "") {
+		field := promoted.field
+		for _, parameterTag := range parameterTags {
+			name, ok := field.Tag.Lookup(parameterTag.tag)
+			if !ok {
+				continue
+			}
+			if name == "" {
+				return schema, fmt.Errorf("%w: field %s of %s has an empty %s tag", ErrQueryStruct, field.Name, t, parameterTag.tag)
+			}
+			if _, exists := params[parameterTag.in][name]; !exists {
+				params[parameterTag.in][name] = parameterFromField(field)
+			}
+			break
+		}
+	}
+
+	if len(params[pathParamsType]) > 0 {
+		schema.PathParams = params[pathParamsType]
+	}
+	if len(params[queryParamType]) > 0 {
+		schema.Querystring = params[queryParamType]
+	}
+	if len(params[headerParamType]) > 0 {
+		schema.Headers = params[headerParamType]
+	}
+	if len(params[cookieParamType]) > 0 {
+		schema.Cookies = params[cookieParamType]
+	}
+	return schema, nil
+}
@@ -0,0 +1,132 @@
+package swagger
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type ListUsersQuery struct {
+	Limit     int     `query:"limit" jsonschema:"required,description=Maximum number of users"`
+	Cursor    *string `query:"cursor"`
+	RequestID string  `header:"X-Request-Id" jsonschema:"description=Request identifier"`
+	Session   string  `cookie:"session" jsonschema:"required"`
+	Ignored   string
+	internal  string `query:"internal"`
+}
+
+type Pagination struct {
+	Limit  int `query:"limit"`
+	Offset int `query:"offset"`
+}
+
+type ListNotesQuery struct {
+	Pagination
+	*Sorting
+	Tag string `query:"tag"`
+}
+
+type Sorting struct {
+	Sort string `query:"sort"`
+}
+
+type SearchQuery struct {
+	Limit  int    `query:"limit" jsonschema:"minimum=1,maximum=100,default=20"`
+	Order  string `query:"order" jsonschema:"enum=asc,enum=desc"`
+	Term   string `query:"q" jsonschema:"required,pattern=^[a-z]+$,description=Words to search\\, separated by spaces"`
+	Locale string `header:"Accept-Language" jsonschema:"minLength=2"`
+}
+
+func TestQueryStruct(t *testing.T) {
+	t.Run("tagged fields are parameters", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.AddRoute(http.MethodGet, "/teams/{teamId}/users", okHandler, Definitions{
+			QueryStruct: ListUsersQuery{},
+			Querystring: ParameterValue{
+				"cursor": {Schema: &Schema{Value: ""}, Description: "Pagination cursor"},
+			},
+		})
+		require.NoError(t, err)
+
+		parameters, err := json.Marshal(router.GetSwaggerSchema().Paths.Value("/teams/{teamId}/users").Get.Parameters)
+		require.NoError(t, err)
+		require.JSONEq(t, `[
+			{"in": "path", "name": "teamId", "required": true, "schema": {"type": "string"}},
+			{"in": "query", "name": "cursor", "description": "Pagination cursor", "schema": {"type": "string"}},
+			{"in": "query", "name": "limit", "description": "Maximum number of users", "required": true, "schema": {"type": "integer"}},
+			{"in": "header", "name": "X-Request-Id", "description": "Request identifier", "schema": {"type": "string"}},
+			{"in": "cookie", "name": "session", "required": true, "schema": {"type": "string"}}
+		]`, string(parameters))
+	})
+
+	t.Run("pointer to struct", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.AddRoute(http.MethodGet, "/users", okHandler, Definitions{
+			QueryStruct: &ListUsersQuery{},
+		})
+		require.NoError(t, err)
+		require.Len(t, router.GetSwaggerSchema().Paths.Value("/users").Get.Parameters, 4)
+	})
+
+	t.Run("embedded structs", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.AddRoute(http.MethodGet, "/notes", okHandler, Definitions{
+			QueryStruct: ListNotesQuery{},
+		})
+		require.NoError(t, err)
+
+		parameters, err := json.Marshal(router.GetSwaggerSchema().Paths.Value("/notes").Get.Parameters)
+		require.NoError(t, err)
+		require.JSONEq(t, `[
+			{"in": "query", "name": "limit", "schema": {"type": "integer"}},
+			{"in": "query", "name": "offset", "schema": {"type": "integer"}},
+			{"in": "query", "name": "sort", "schema": {"type": "string"}},
+			{"in": "query", "name": "tag", "schema": {"type": "string"}}
+		]`, string(parameters))
+	})
+
+	t.Run("constraints of the tags", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.AddRoute(http.MethodGet, "/search", okHandler, Definitions{
+			QueryStruct: SearchQuery{},
+		})
+		require.NoError(t, err)
+
+		parameters, err := json.Marshal(router.GetSwaggerSchema().Paths.Value("/search").Get.Parameters)
+		require.NoError(t, err)
+		require.JSONEq(t, `[
+			{"in": "query", "name": "limit", "schema": {"type": "integer", "minimum": 1, "maximum": 100, "default": 20}},
+			{"in": "query", "name": "order", "schema": {"type": "string", "enum": ["asc", "desc"]}},
+			{"in": "query", "name": "q", "description": "Words to search, separated by spaces", "required": true, "schema": {"type": "string", "pattern": "^[a-z]+$"}},
+			{"in": "header", "name": "Accept-Language", "schema": {"type": "string", "minLength": 2}}
+		]`, string(parameters))
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.AddRoute(http.MethodGet, "/users", okHandler, Definitions{
+			QueryStruct: map[string]string{},
+		})
+		require.ErrorIs(t, err, ErrQueryStruct)
+		require.EqualError(t, err, "invalid query struct: expected a struct, got map[string]string")
+	})
+
+	t.Run("empty tag", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.AddRoute(http.MethodGet, "/users", okHandler, Definitions{
+			QueryStruct: struct {
+				Limit int `query:""`
+			}{},
+		})
+		require.ErrorIs(t, err, ErrQueryStruct)
+		require.Contains(t, err.Error(), "field Limit of struct { Limit int \"query:\\\"\\\"\" } has an empty query tag")
+	})
+}
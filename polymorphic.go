@@ -0,0 +1,188 @@
+package swagger
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+)
+
+// ErrPolymorphic is returned when a polymorphic schema is invalid.
+var ErrPolymorphic = errors.New("invalid polymorphic schema")
+
+// Polymorphic describes a value which can be one of several Go types.
+// It can be used as the Value of a Content or a Schema, or registered for an
+// interface type with RegisterInterface.
+type Polymorphic struct {
+	keyword      string
+	variants     []any
+	propertyName string
+}
+
+// DiscriminatorValuer is implemented by the variants of a Polymorphic schema
+// to set their value of the discriminator property in the discriminator
+// mapping. Default to the component name of the variant.
+type DiscriminatorValuer interface {
+	DiscriminatorValue() string
+}
+
+// OneOf returns a Polymorphic schema matching exactly one of the variants.
+func OneOf(variants ...any) *Polymorphic {
+	return &Polymorphic{keyword: "oneOf", variants: variants}
+}
+
+// AnyOf returns a Polymorphic schema matching any of the variants.
+func AnyOf(variants ...any) *Polymorphic {
+	return &Polymorphic{keyword: "anyOf", variants: variants}
+}
+
+// Discriminator sets the property identifying the variant of the value.
+// The variants must be named structs, referenced as components, and are
+// listed in the discriminator mapping.
+func (p *Polymorphic) Discriminator(propertyName string) *Polymorphic {
+	p.propertyName = propertyName
+	return p
+}
+
+// validate checks that the variants of p can be documented.
+func (p *Polymorphic) validate() error {
+	if len(p.variants) == 0 {
+		return fmt.Errorf("%w: no variants", ErrPolymorphic)
+	}
+	for _, variant := range p.variants {
+		if variant == nil {
+			return fmt.Errorf("%w: nil variant", ErrPolymorphic)
+		}
+		if p.propertyName == "" {
+			continue
+		}
+		t := reflect.TypeOf(variant)
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || t.Name() == "" {
+			return fmt.Errorf("%w: variant %T of a schema with discriminator must be a named struct", ErrPolymorphic, variant)
+		}
+	}
+	return nil
+}
+
+// RegisterInterface documents every occurrence of an interface type, given
+// as a nil pointer such as (*Animal)(nil), with a Polymorphic schema. The
+// interface is referenced as a component. Interfaces are shared by groups and
+// host routers.
+//
+//	router.RegisterInterface((*Animal)(nil), swagger.OneOf(Cat{}, Dog{}).Discriminator("kind"))
+func (r *Router[_, _, _]) RegisterInterface(iface any, schema *Polymorphic) error {
+	t := reflect.TypeOf(iface)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+		return fmt.Errorf("%w: expected a pointer to an interface, got %T", ErrPolymorphic, iface)
+	}
+	if schema == nil {
+		return fmt.Errorf("%w: no schema for %s", ErrPolymorphic, t.Elem())
+	}
+	if err := schema.validate(); err != nil {
+		return err
+	}
+	if _, exists := r.interfaces[t.Elem()]; exists {
+		return fmt.Errorf("%w %s: already registered", ErrPolymorphic, t.Elem())
+	}
+	registered := *schema
+	r.interfaces[t.Elem()] = &registered
+	return nil
+}
+
+// polymorphicSchema reflects the variants of p with reflector, adding the
+// definitions of the variants to definitions.
+func polymorphicSchema(reflector *jsonschema.Reflector, definitions jsonschema.Definitions, p *Polymorphic) *jsonschema.Schema {
+	variantReflector := *reflector
+	variantReflector.ExpandedStruct = false
+
+	variants := make([]*jsonschema.Schema, 0, len(p.variants))
+	mapping := map[string]string{}
+	for _, variant := range p.variants {
+		schema := variantReflector.ReflectFromType(reflect.TypeOf(variant))
+		for defName, def := range schema.Definitions {
+			if _, exists := definitions[defName]; !exists {
+				definitions[defName] = def
+			}
+		}
+		schema.Definitions = nil
+		schema.Version = ""
+		schema.ID = ""
+		variants = append(variants, schema)
+
+		if name, ok := strings.CutPrefix(schema.Ref, "#/$defs/"); ok {
+			value := name
+			if valuer, ok := variant.(DiscriminatorValuer); ok {
+				value = valuer.DiscriminatorValue()
+			}
+			mapping[value] = "#/components/schemas/" + name
+		}
+	}
+
+	schema := &jsonschema.Schema{}
+	if p.keyword == "anyOf" {
+		schema.AnyOf = variants
+	} else {
+		schema.OneOf = variants
+	}
+	if p.propertyName != "" {
+		schema.Extras = map[string]any{
+			"discriminator": map[string]any{
+				"propertyName": p.propertyName,
+				"mapping":      mapping,
+			},
+		}
+	}
+	return schema
+}
+
+// interfaceMapper returns a jsonschema Mapper documenting the registered
+// interfaces with their Polymorphic schema, wrapping the Mapper of the
+// reflector. Variants are reflected with the Mapper the reflector has when
+// the interface is met.
+func interfaceMapper(reflector *jsonschema.Reflector, definitions jsonschema.Definitions, interfaces map[reflect.Type]*Polymorphic) func(reflect.Type) *jsonschema.Schema {
+	mapper := reflector.Mapper
+	inProgress := map[reflect.Type]bool{}
+
+	return func(t reflect.Type) *jsonschema.Schema {
+		if mapper != nil {
+			if schema := mapper(t); schema != nil {
+				return schema
+			}
+		}
+		polymorphic, ok := interfaces[t]
+		if !ok {
+			return nil
+		}
+
+		name := ""
+		if !reflector.DoNotReference {
+			name = t.Name()
+			if reflector.Namer != nil {
+				if customName := reflector.Namer(t); customName != "" {
+					name = customName
+				}
+			}
+		}
+		if name == "" {
+			if inProgress[t] {
+				// A recursive interface can not be inlined
+				return &jsonschema.Schema{}
+			}
+			inProgress[t] = true
+			defer delete(inProgress, t)
+			return polymorphicSchema(reflector, definitions, polymorphic)
+		}
+
+		if _, exists := definitions[name]; !exists {
+			// Reserve the name, so that recursive references are resolved
+			definitions[name] = &jsonschema.Schema{}
+			*definitions[name] = *polymorphicSchema(reflector, definitions, polymorphic)
+		}
+		return &jsonschema.Schema{Ref: "#/$defs/" + name}
+	}
+}
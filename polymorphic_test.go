@@ -0,0 +1,143 @@
+package swagger
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Animal interface {
+	Sound() string
+}
+
+type Cat struct {
+	Kind  string `json:"kind"`
+	Lives int    `json:"lives"`
+}
+
+func (Cat) Sound() string { return "meow" }
+
+type Dog struct {
+	Kind  string `json:"kind"`
+	Breed string `json:"breed"`
+}
+
+func (Dog) Sound() string { return "woof" }
+
+func (Dog) DiscriminatorValue() string { return "dog" }
+
+type Owner struct {
+	Name string   `json:"name"`
+	Pet  Animal   `json:"pet"`
+	Pets []Animal `json:"pets"`
+}
+
+type Zoo struct {
+	Keeper Animal `json:"keeper"`
+}
+
+func (Zoo) Sound() string { return "" }
+
+func TestPolymorphic(t *testing.T) {
+	marshal := func(t *testing.T, v any) string {
+		t.Helper()
+
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("response body with discriminator", func(t *testing.T) {
+		router := setupRouter(t)
+
+		_, err := router.AddRoute(http.MethodGet, "/pets/{id}", okHandler, Definitions{
+			Responses: map[int]ContentValue{
+				200: {Content: Content{jsonType: {Value: OneOf(Cat{}, &Dog{}).Discriminator("kind")}}},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		schema := router.GetSwaggerSchema().Paths.Value("/pets/{id}").Get.Responses.Status(200).Value.Content.Get(jsonType).Schema
+		require.JSONEq(t, `{
+			"oneOf": [
+				{"$ref": "#/components/schemas/Cat"},
+				{"$ref": "#/components/schemas/Dog"}
+			],
+			"discriminator": {
+				"propertyName": "kind",
+				"mapping": {
+					"Cat": "#/components/schemas/Cat",
+					"dog": "#/components/schemas/Dog"
+				}
+			}
+		}`, marshal(t, schema))
+		require.Contains(t, router.GetSwaggerSchema().Components.Schemas, "Cat")
+		require.Contains(t, router.GetSwaggerSchema().Components.Schemas, "Dog")
+	})
+
+	t.Run("anyOf without discriminator", func(t *testing.T) {
+		router := setupRouter(t)
+
+		schema, err := router.getSchemaFromInterface(AnyOf("", 0), false)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"anyOf": [{"type": "string"}, {"type": "integer"}]}`, marshal(t, schema))
+	})
+
+	t.Run("registered interface", func(t *testing.T) {
+		router := setupRouter(t)
+		require.NoError(t, router.RegisterInterface((*Animal)(nil), OneOf(Cat{}, Dog{}, Zoo{}).Discriminator("kind")))
+
+		_, err := router.AddRoute(http.MethodPost, "/owners", okHandler, Definitions{
+			RequestBody: &ContentValue{Content: Content{jsonType: {Value: Owner{}}}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		schemas := router.GetSwaggerSchema().Components.Schemas
+		require.JSONEq(t, `{
+			"name": {"type": "string"},
+			"pet": {"$ref": "#/components/schemas/Animal"},
+			"pets": {"type": "array", "items": {"$ref": "#/components/schemas/Animal"}}
+		}`, marshal(t, schemas["Owner"].Value.Properties))
+		require.JSONEq(t, `{
+			"oneOf": [
+				{"$ref": "#/components/schemas/Cat"},
+				{"$ref": "#/components/schemas/Dog"},
+				{"$ref": "#/components/schemas/Zoo"}
+			],
+			"discriminator": {
+				"propertyName": "kind",
+				"mapping": {
+					"Cat": "#/components/schemas/Cat",
+					"dog": "#/components/schemas/Dog",
+					"Zoo": "#/components/schemas/Zoo"
+				}
+			}
+		}`, marshal(t, schemas["Animal"].Value))
+		require.JSONEq(t, `{"keeper": {"$ref": "#/components/schemas/Animal"}}`, marshal(t, schemas["Zoo"].Value.Properties))
+	})
+
+	t.Run("invalid schemas", func(t *testing.T) {
+		router := setupRouter(t)
+
+		err := router.RegisterInterface(Cat{}, OneOf(Cat{}))
+		require.EqualError(t, err, "invalid polymorphic schema: expected a pointer to an interface, got swagger.Cat")
+
+		err = router.RegisterInterface((*Animal)(nil), OneOf())
+		require.EqualError(t, err, "invalid polymorphic schema: no variants")
+
+		require.NoError(t, router.RegisterInterface((*Animal)(nil), OneOf(Cat{})))
+		err = router.RegisterInterface((*Animal)(nil), OneOf(Dog{}))
+		require.EqualError(t, err, "invalid polymorphic schema swagger.Animal: already registered")
+
+		_, err = router.AddRoute(http.MethodGet, "/pets", okHandler, Definitions{
+			Responses: map[int]ContentValue{
+				200: {Content: Content{jsonType: {Value: OneOf(Cat{}, "").Discriminator("kind")}}},
+			},
+		})
+		require.ErrorContains(t, err, "variant string of a schema with discriminator must be a named struct")
+	})
+}
@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"maps"
 	"path"
 	"reflect"
 	"regexp"
-	"runtime"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -25,8 +28,20 @@ var (
 	ErrPathParams = errors.New("errors generating path parameters schema")
 	// ErrQuerystring indicates failure generating querystring parameter schemas
 	ErrQuerystring = errors.New("errors generating querystring schema")
+	// ErrHeaders indicates failure generating header parameter schemas
+	ErrHeaders = errors.New("errors generating headers schema")
+	// ErrCookies indicates failure generating cookie parameter schemas
+	ErrCookies = errors.New("errors generating cookies schema")
 )
 
+// parameterErrors maps parameter locations to the error reporting their schema failures
+var parameterErrors = map[string]error{
+	pathParamsType:  ErrPathParams,
+	queryParamType:  ErrQuerystring,
+	headerParamType: ErrHeaders,
+	cookieParamType: ErrCookies,
+}
+
 // AddRawRoute adds a route with explicit OpenAPI Operation definition.
 // This lower-level method allows full control over the OpenAPI operation definition.
 // Parameters:
@@ -40,6 +55,12 @@ var (
 //   - Route: Framework-specific route object
 //   - error: Validation error if operation is invalid
 func (r *Router[HandlerFunc, MiddlewareFunc, Route]) AddRawRoute(method string, routePath string, handler HandlerFunc, operation Operation, middleware ...MiddlewareFunc) (Route, error) {
+	return r.addRawRoute(method, routePath, handler, handlerName(handler), operation, middleware...)
+}
+
+// addRawRoute adds the route as AddRawRoute does, recording name as the name
+// of its handler.
+func (r *Router[HandlerFunc, MiddlewareFunc, Route]) addRawRoute(method string, routePath string, handler HandlerFunc, name string, operation Operation, middleware ...MiddlewareFunc) (Route, error) {
 	op := operation.Operation
 	if op == nil {
 		op = openapi3.NewOperation()
@@ -48,10 +69,34 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) AddRawRoute(method string,
 		}
 	}
 
+	if r.defaults != nil {
+		mergeDefaults(op, r.defaults)
+	}
+
 	pathWithPrefix := path.Join(r.pathPrefix, routePath)
 	oasPath := r.router.TransformPathToOasPath(pathWithPrefix)
+	r.setOperationID(method, oasPath, op)
+	routeMiddleware, err := r.routeMiddleware(method, oasPath, op)
+	if err != nil {
+		return getZero[Route](), err
+	}
 	r.swaggerSchema.AddOperation(oasPath, method, op)
 
+	middlewareCount := len(middleware)
+	if len(routeMiddleware) > 0 {
+		// gswagger middleware runs after the route middleware, right before the handler
+		middleware = append(append([]MiddlewareFunc{}, middleware...), routeMiddleware...)
+	}
+
+	r.addRouteInfo(RouteInfo{
+		Method:          method,
+		Path:            path.Join(r.pathPrefix, routePath),
+		OASPath:         oasPath,
+		OperationID:     op.OperationID,
+		Handler:         name,
+		MiddlewareCount: middlewareCount,
+	})
+
 	pathWithPrefix = routePath
 	if !r.isSubrouter {
 		pathWithPrefix = path.Join(r.pathPrefix, routePath)
@@ -66,16 +111,21 @@ type Content map[string]Schema
 
 // Schema defines the structure of request/response data
 type Schema struct {
-	Value                     any  // Go type to generate schema from
-	AllowAdditionalProperties bool // Whether to allow extra fields
+	Value                     any      // Go type to generate schema from
+	AllowAdditionalProperties bool     // Whether to allow extra fields
+	Examples                  Examples // Named example values
+	Ref                       string   // Name of a registered schema, replacing Value
+
+	field *reflect.StructField // Struct field reflected with its tags, replacing Value
 }
 
 // Parameter defines an API parameter (path, query, header, cookie)
 type Parameter struct {
-	Content     Content // Media type schemas (alternative to Schema)
-	Schema      *Schema // Parameter schema definition
-	Description string  // Human-readable description
-	Required    bool    // Whether parameter is required
+	Content     Content  // Media type schemas (alternative to Schema)
+	Schema      *Schema  // Parameter schema definition
+	Description string   // Human-readable description
+	Required    bool     // Whether parameter is required
+	Examples    Examples // Named example values, the examples of Schema taking precedence
 }
 
 // ParameterValue maps parameter names to their definitions
@@ -83,19 +133,34 @@ type ParameterValue map[string]Parameter
 
 // ParameterDefinition defines a reusable parameter component
 type ParameterDefinition struct {
-	In          string  // Location (path, query, header, cookie)
-	Required    bool    // Whether parameter is required
+	In          string   // Location (path, query, header, cookie)
+	Required    bool     // Whether parameter is required
+	Description string   // Human-readable description
+	Content     Content  // Media type schemas (alternative to Schema)
+	Schema      *Schema  // Parameter schema definition
+	Examples    Examples // Named example values, the examples of Schema taking precedence
+	Ref         string   // Name of a registered parameter, replacing the other fields
+}
+
+// Header defines a response header
+type Header struct {
+	Schema      *Schema // Header schema definition, default to a string
 	Description string  // Human-readable description
-	Content     Content // Media type schemas (alternative to Schema)
-	Schema      *Schema // Parameter schema definition
+	Required    bool    // Whether header is always sent
+	Deprecated  bool    // Whether header is deprecated
+	Example     any     // Example value
+	Ref         string  // Name of a registered header, replacing the other fields
 }
 
 // ContentValue defines request/response body content
 type ContentValue struct {
-	Content     Content           // Media type schemas
-	Description string            // Human-readable description
-	Headers     map[string]string // Response headers
-	Required    bool              // Whether body is required
+	Content           Content           // Media type schemas
+	Description       string            // Human-readable description
+	Headers           map[string]string // Response headers, as string headers with their description
+	HeaderDefinitions map[string]Header // Response headers, taking precedence over Headers
+	Examples          Examples          // Named example values of every media type, the examples of each Schema taking precedence
+	Required          bool              // Whether body is required
+	Ref               string            // Name of a registered response or request body, replacing the other fields
 }
 
 // SecurityRequirements lists required security schemes
@@ -106,6 +171,7 @@ type SecurityRequirement map[string][]string
 
 // Definitions provides OpenAPI schema definitions for a route
 type Definitions struct {
+	OperationID string                         // Unique operation identifier, default from Options.OperationID
 	Extensions  map[string]any                 // OpenAPI extensions
 	Tags        []string                       // Logical grouping tags
 	Summary     string                         // Short summary
@@ -116,6 +182,7 @@ type Definitions struct {
 	Querystring ParameterValue                 // Query parameters
 	Headers     ParameterValue                 // Header parameters
 	Cookies     ParameterValue                 // Cookie parameters
+	QueryStruct any                            // Struct whose tagged fields are path, query, header or cookie parameters
 	RequestBody *ContentValue                  // Request body definition
 	Responses   map[int]ContentValue           // Response definitions by status code
 	Security    SecurityRequirements           // Security requirements
@@ -131,8 +198,10 @@ type Definitions struct {
 func newOperationFromDefinition(schema Definitions) Operation {
 	operation := NewOperation()
 	operation.Responses = &openapi3.Responses{}
+	operation.OperationID = schema.OperationID
 	operation.Tags = schema.Tags
-	operation.Extensions = schema.Extensions
+	// The extensions are copied, since the defaults of the router are merged into them
+	operation.Extensions = maps.Clone(schema.Extensions)
 	operation.addSecurityRequirements(schema.Security)
 	operation.Description = schema.Description
 	operation.Summary = schema.Summary
@@ -170,8 +239,22 @@ const (
 //   - Route: Framework-specific route object
 //   - error: Validation error if schema is invalid
 func (r *Router[HandlerFunc, MiddlewareFunc, Route]) AddRoute(method string, path string, handler HandlerFunc, schema Definitions, middleware ...MiddlewareFunc) (Route, error) {
+	operation, err := r.newOperation(method, path, schema)
+	if err != nil {
+		return getZero[Route](), err
+	}
+	return r.AddRawRoute(method, path, handler, operation, middleware...)
+}
+
+// newOperation returns the operation of the route method path described by schema.
+func (r Router[_, _, _]) newOperation(method string, path string, schema Definitions) (Operation, error) {
 	operation := newOperationFromDefinition(schema)
 
+	schema, err := withStructParameters(schema)
+	if err != nil {
+		return Operation{}, err
+	}
+
 	// Collect all parameters from different sources
 	allParams := make(map[string]ParameterDefinition)
 
@@ -192,6 +275,7 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) AddRoute(method string, pat
 				Description: param.Description,
 				Content:     param.Content,
 				Schema:      param.Schema,
+				Examples:    param.Examples,
 			}
 		}
 	}
@@ -205,6 +289,7 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) AddRoute(method string, pat
 				Description: param.Description,
 				Content:     param.Content,
 				Schema:      param.Schema,
+				Examples:    param.Examples,
 			}
 		}
 	}
@@ -218,6 +303,7 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) AddRoute(method string, pat
 				Description: param.Description,
 				Content:     param.Content,
 				Schema:      param.Schema,
+				Examples:    param.Examples,
 			}
 		}
 	}
@@ -231,10 +317,22 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) AddRoute(method string, pat
 				Description: param.Description,
 				Content:     param.Content,
 				Schema:      param.Schema,
+				Examples:    param.Examples,
 			}
 		}
 	}
 
+	// Registered parameters are sorted by the location of their component
+	for name, paramDef := range allParams {
+		if paramDef.Ref == "" {
+			continue
+		}
+		if component, err := r.parameterComponent(paramDef.Ref); err == nil {
+			paramDef.In = component.Value.In
+			allParams[name] = paramDef
+		}
+	}
+
 	// Convert map to slice for sorting
 	var sortedParamNames []string
 	for name := range allParams {
@@ -252,63 +350,185 @@ func (r *Router[HandlerFunc, MiddlewareFunc, Route]) AddRoute(method string, pat
 	})
 
 	// Add sorted parameters to the operation
+	var paramErrs []error
 	for _, name := range sortedParamNames {
 		paramDef := allParams[name]
-		param := &openapi3.Parameter{
-			In:          paramDef.In,
-			Name:        name,
-			Required:    paramDef.Required,
-			Description: paramDef.Description,
-		}
-
-		if paramDef.Content != nil {
-			content, err := r.addContentToOASSchema(paramDef.Content)
-			if err != nil {
-				// Log or handle the error appropriately, but don't fail AddRoute for a single parameter
-				continue
-			}
-			param.Content = content
-		} else if paramDef.Schema != nil {
-			schema, err := r.getSchemaFromInterface(paramDef.Schema.Value, paramDef.Schema.AllowAdditionalProperties)
-			if err != nil {
-				// Log or handle the error appropriately
-				continue
-			}
-			param.Schema = schema
+		param, err := r.newParameterRef(name, paramDef)
+		if err != nil {
+			paramErrs = append(paramErrs, newParameterError(method, path, paramDef.In, name, err))
+			continue
 		}
-		operation.AddParameter(param)
+		operation.Parameters = append(operation.Parameters, param)
+	}
+	if err := r.reportParameterErrors(paramErrs); err != nil {
+		return Operation{}, err
 	}
 
-	err := r.resolveRequestBodySchema(schema.RequestBody, operation)
+	err = r.resolveRequestBodySchema(schema.RequestBody, operation)
 	if err != nil {
-		return getZero[Route](), fmt.Errorf("%w: %s", ErrRequestBody, err)
+		return Operation{}, fmt.Errorf("%w: %s", ErrRequestBody, err)
 	}
 
 	err = r.resolveResponsesSchema(schema.Responses, operation)
 	if err != nil {
-		return getZero[Route](), fmt.Errorf("%w: %s", ErrResponses, err)
+		return Operation{}, fmt.Errorf("%w: %s", ErrResponses, err)
 	}
 
-	return r.AddRawRoute(method, path, handler, operation, middleware...)
+	return operation, nil
+}
+
+// newParameterRef returns the parameter name, or a reference to its
+// component if it is registered.
+func (r Router[_, _, _]) newParameterRef(name string, paramDef ParameterDefinition) (*openapi3.ParameterRef, error) {
+	if paramDef.Ref != "" {
+		component, err := r.parameterComponent(paramDef.Ref)
+		if err != nil {
+			return nil, err
+		}
+		return &openapi3.ParameterRef{Ref: componentRef("parameters", paramDef.Ref), Value: component.Value}, nil
+	}
+
+	param := &openapi3.Parameter{
+		In:          paramDef.In,
+		Name:        name,
+		Required:    paramDef.Required,
+		Description: paramDef.Description,
+	}
+	if paramDef.Content != nil {
+		content, err := r.addContentToOASSchema(paramDef.Content, paramDef.Examples, requestDirection)
+		if err != nil {
+			return nil, err
+		}
+		param.Content = content
+	} else if paramDef.Schema != nil {
+		var err error
+		if paramDef.Schema.field != nil {
+			err = r.addFieldSchemaToParameter(param, *paramDef.Schema.field)
+		} else {
+			param.Schema, err = r.newSchemaRef(*paramDef.Schema, requestDirection)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		param.Examples, err = newOASExamples(paramDef.Examples, paramDef.Schema.Examples)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &openapi3.ParameterRef{Value: param}, nil
+}
+
+// addFieldSchemaToParameter documents param with the schema of a struct
+// field. The field is reflected inside a struct of its own, so that the
+// constraints of its tags are kept, and its required flag and description
+// are moved to the parameter.
+func (r Router[_, _, _]) addFieldSchemaToParameter(param *openapi3.Parameter, field reflect.StructField) error {
+	nameTag := "json"
+	if r.reflectorOptions != nil && r.reflectorOptions.FieldNameTag != "" {
+		nameTag = r.reflectorOptions.FieldNameTag
+	}
+	holder := reflect.StructOf([]reflect.StructField{{
+		Name: "Value",
+		Type: field.Type,
+		Tag:  reflect.StructTag(fmt.Sprintf(`%s:"value,omitempty" %s`, nameTag, field.Tag)),
+	}})
+	holderSchema, err := r.getDirectedSchemaFromInterface(reflect.Zero(holder).Interface(), false, requestDirection)
+	if err != nil {
+		return err
+	}
+
+	property := holderSchema.Value.Properties["value"]
+	if property == nil {
+		// The field is hidden from the request by its tags
+		param.Schema, err = r.getDirectedSchemaFromInterface(reflect.Zero(field.Type).Interface(), false, requestDirection)
+		return err
+	}
+	if slices.Contains(holderSchema.Value.Required, "value") {
+		param.Required = true
+	}
+	if property.Ref == "" && param.Description == "" {
+		param.Description = property.Value.Description
+		property.Value.Description = ""
+	}
+	param.Schema = property
+	return nil
+}
+
+// newParameterError reports a parameter whose schema cannot be generated.
+func newParameterError(method, path, in, name string, err error) error {
+	if locationErr, ok := parameterErrors[in]; ok {
+		return fmt.Errorf("%w for %s %s: parameter %q: %w", locationErr, method, path, name, err)
+	}
+	return fmt.Errorf("errors generating %s parameter schema for %s %s: parameter %q: %w", in, method, path, name, err)
+}
+
+// reportParameterErrors returns the parameter errors joined in strict mode.
+// Otherwise the parameters are dropped from the operation, and their errors
+// are passed to the warning handler.
+func (r *Router[_, _, _]) reportParameterErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	if r.options.strict {
+		return errors.Join(errs...)
+	}
+
+	r.warn(errs...)
+	return nil
+}
+
+// warn passes each of errs to the warning handler.
+func (r Router[_, _, _]) warn(errs ...error) {
+	onWarning := r.options.warningHandler
+	if onWarning == nil {
+		onWarning = logWarning
+	}
+	for _, err := range errs {
+		onWarning(err)
+	}
+}
+
+// logWarning is the default warning handler.
+func logWarning(err error) {
+	log.Printf("gswagger: %s", err)
 }
 
 func (r Router[_, _, _]) resolveRequestBodySchema(bodySchema *ContentValue, operation Operation) error {
 	if bodySchema == nil {
 		return nil
 	}
-	content, err := r.addContentToOASSchema(bodySchema.Content)
+	requestBody, err := r.newRequestBodyRef(*bodySchema)
 	if err != nil {
 		return err
 	}
+	operation.RequestBody = requestBody
+	return nil
+}
+
+// newRequestBodyRef returns the request body described by v, or a reference
+// to its component if it is registered.
+func (r Router[_, _, _]) newRequestBodyRef(v ContentValue) (*openapi3.RequestBodyRef, error) {
+	if v.Ref != "" {
+		component, err := r.requestBodyComponent(v.Ref)
+		if err != nil {
+			return nil, err
+		}
+		return &openapi3.RequestBodyRef{Ref: componentRef("requestBodies", v.Ref), Value: component.Value}, nil
+	}
+
+	content, err := r.addContentToOASSchema(v.Content, v.Examples, requestDirection)
+	if err != nil {
+		return nil, err
+	}
 
 	requestBody := openapi3.NewRequestBody().WithContent(content)
 
-	requestBody.WithDescription(bodySchema.Description)
+	requestBody.WithDescription(v.Description)
 	// Explicitly set required based on the ContentValue's Required field
-	requestBody.Required = bodySchema.Required
+	requestBody.Required = v.Required
 
-	operation.AddRequestBody(requestBody)
-	return nil
+	return &openapi3.RequestBodyRef{Value: requestBody}, nil
 }
 
 func (r Router[_, _, _]) resolveResponsesSchema(responses map[int]ContentValue, operation Operation) error {
@@ -323,312 +543,243 @@ func (r Router[_, _, _]) resolveResponsesSchema(responses map[int]ContentValue,
 	sort.Ints(statusCodes)
 
 	for _, statusCode := range statusCodes {
-		v := responses[statusCode]
-		response := openapi3.NewResponse()
-		content, err := r.addContentToOASSchema(v.Content)
+		response, err := r.newResponseRef(responses[statusCode])
 		if err != nil {
 			return err
 		}
-		response = response.WithContent(content)
-		response = response.WithDescription(v.Description)
-
-		if len(v.Headers) > 0 {
-			response.Headers = make(map[string]*openapi3.HeaderRef)
-			// Sort header names for consistent order
-			var headerNames []string
-			for name := range v.Headers {
-				headerNames = append(headerNames, name)
-			}
-			sort.Strings(headerNames)
-
-			for _, headerName := range headerNames {
-				headerDesc := v.Headers[headerName]
-				header := &openapi3.Header{
-					Parameter: openapi3.Parameter{
-						Description: headerDesc,
-						Schema: &openapi3.SchemaRef{
-							Value: openapi3.NewStringSchema(),
-						},
-					},
-				}
-				response.Headers[headerName] = &openapi3.HeaderRef{
-					Value: header,
-				}
-			}
+		if response.Ref == "" {
+			operation.AddResponse(statusCode, response.Value)
+			continue
 		}
-
-		operation.AddResponse(statusCode, response)
+		if operation.Responses == nil {
+			operation.Responses = &openapi3.Responses{}
+		}
+		operation.Responses.Set(strconv.Itoa(statusCode), response)
 	}
 
 	return nil
 }
 
-func isPrimitiveType(v any) bool {
-	switch v.(type) {
-	case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
-		return true
-	default:
-		return false
+// newResponseRef returns the response described by v, or a reference to its
+// component if it is registered.
+func (r Router[_, _, _]) newResponseRef(v ContentValue) (*openapi3.ResponseRef, error) {
+	if v.Ref != "" {
+		component, err := r.responseComponent(v.Ref)
+		if err != nil {
+			return nil, err
+		}
+		return &openapi3.ResponseRef{Ref: componentRef("responses", v.Ref), Value: component.Value}, nil
 	}
-}
 
-func isProblematicEmbeddedType(field reflect.StructField, parentType reflect.Type) bool {
-	if !field.Anonymous {
-		return false
+	response := openapi3.NewResponse()
+	content, err := r.addContentToOASSchema(v.Content, v.Examples, responseDirection)
+	if err != nil {
+		return nil, err
 	}
+	response = response.WithContent(content)
+	response = response.WithDescription(v.Description)
 
-	fieldType := field.Type
-	if fieldType.Kind() == reflect.Ptr {
-		fieldType = fieldType.Elem()
+	headers, err := r.resolveResponseHeaders(v)
+	if err != nil {
+		return nil, err
 	}
+	response.Headers = headers
 
-	// Only flag direct self-references
-	return fieldType == parentType
+	return &openapi3.ResponseRef{Value: response}, nil
 }
 
-type typeTrace struct {
-	PkgPath  string // Package import path
-	TypeName string // Type name
-	Type     reflect.Type
-	File     string
-	Line     int
-	Field    string // For struct fields
-	Method   string // For interface methods
-	MapKey   string // For map keys
-	SliceIdx int    // For slice/array indices
-}
-
-func (r Router[_, _, _]) checkForCycles(v any, path []typeTrace) error {
-	if isPrimitiveType(v) {
-		return nil
+// resolveResponseHeaders returns the headers of a response, from the
+// HeaderDefinitions and the Headers shorthand.
+func (r Router[_, _, _]) resolveResponseHeaders(v ContentValue) (openapi3.Headers, error) {
+	if len(v.Headers) == 0 && len(v.HeaderDefinitions) == 0 {
+		return nil, nil
 	}
-
-	origVal := reflect.ValueOf(v)
-	if !origVal.IsValid() {
-		return nil
+	headers := make(map[string]Header, len(v.Headers)+len(v.HeaderDefinitions))
+	for name, description := range v.Headers {
+		headers[name] = Header{Description: description}
+	}
+	for name, header := range v.HeaderDefinitions {
+		headers[name] = header
 	}
 
-	t := origVal.Type()
-
-	// Check for problematic embedded types before proceeding
-	if origVal.Kind() == reflect.Struct {
-		for i := 0; i < origVal.NumField(); i++ {
-			field := t.Field(i)
-			if isProblematicEmbeddedType(field, t) {
-				return fmt.Errorf("invalid schema: embedded type %s creates an infinite recursion in type %s",
-					field.Type, t)
-			}
+	result := make(openapi3.Headers, len(headers))
+	for name, header := range headers {
+		headerRef, err := r.newHeaderRef(header)
+		if err != nil {
+			return nil, fmt.Errorf("header %q: %w", name, err)
 		}
+		result[name] = headerRef
 	}
+	return result, nil
+}
 
-	// Get the dereferenced value if it's a pointer
-	val := origVal
-	if val.Kind() == reflect.Ptr {
-		if val.IsNil() {
-			return nil
+// newHeaderRef returns the response header described by header, or a
+// reference to its component if it is registered.
+func (r Router[_, _, _]) newHeaderRef(header Header) (*openapi3.HeaderRef, error) {
+	if header.Ref != "" {
+		component, err := r.headerComponent(header.Ref)
+		if err != nil {
+			return nil, err
 		}
-		val = val.Elem()
+		return &openapi3.HeaderRef{Ref: componentRef("headers", header.Ref), Value: component.Value}, nil
 	}
 
-	// Skip cycle checking for invalid/zero values after dereferencing
-	if !val.IsValid() {
-		return nil
+	schema := &openapi3.SchemaRef{Value: openapi3.NewStringSchema()}
+	if header.Schema != nil {
+		var err error
+		schema, err = r.newSchemaRef(*header.Schema, responseDirection)
+		if err != nil {
+			return nil, err
+		}
 	}
+	return &openapi3.HeaderRef{
+		Value: &openapi3.Header{
+			Parameter: openapi3.Parameter{
+				Description: header.Description,
+				Required:    header.Required,
+				Deprecated:  header.Deprecated,
+				Example:     header.Example,
+				Schema:      schema,
+			},
+		},
+	}, nil
+}
 
-	file := ""
-	line := 0
+// typeTrace is a step of a path in the type graph.
+type typeTrace struct {
+	Type  reflect.Type
+	Field string // Struct field leading to the next type, empty for elements
+}
 
-	// Only try to get source location for pointer types
-	if origVal.Kind() == reflect.Ptr {
-		if pc := origVal.Pointer(); pc != 0 {
-			if f := runtime.FuncForPC(pc); f != nil {
-				file, line = f.FileLine(pc)
-			}
-		}
+// isReferencedType reports whether the reflector documents t as a component
+// referenced with $ref, which breaks recursion. root is the reflected type,
+// inlined with ExpandedStruct.
+func isReferencedType(reflector *jsonschema.Reflector, t, root reflect.Type) bool {
+	if reflector.DoNotReference || (reflector.ExpandedStruct && t == root) {
+		return false
 	}
-
-	// Get the dereferenced value if it's a pointer
-	val = origVal
-	if val.Kind() == reflect.Ptr {
-		if val.IsNil() {
-			return nil
-		}
-		val = val.Elem()
+	switch t.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+	default:
+		return false
+	}
+	if reflector.Namer != nil && reflector.Namer(t) != "" {
+		return true
 	}
+	return t.Name() != ""
+}
 
-	// Skip cycle checking for invalid/zero values after dereferencing
-	if !val.IsValid() {
+// checkForCycles returns an error if a type reachable from t is recursive and
+// cannot be represented: every cycle must go through a type documented as a
+// referenced component, such as a named struct.
+func checkForCycles(t reflect.Type, reflector *jsonschema.Reflector) error {
+	if t == nil {
 		return nil
 	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	root := t
+	fieldNameTag := reflectorFieldNameTag(reflector)
+
+	checked := map[reflect.Type]bool{}
+	var walk func(t reflect.Type, path []typeTrace) error
+	walk = func(t reflect.Type, path []typeTrace) error {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if checked[t] {
+			return nil
+		}
+		if reflector.Mapper != nil && reflector.Mapper(t) != nil {
+			return nil
+		}
+		if t.Implements(customSchemaType) {
+			return nil
+		}
 
-	// Check for cycles
-	for _, entry := range path {
-		if entry.Type == t {
-			var trace strings.Builder
-
-			// Build cycle summary
-			trace.WriteString("cycle detected in type graph:\n")
-			trace.WriteString("Cycle path summary:\n")
-			for i := 0; i < len(path); i++ {
-				if i > 0 {
-					trace.WriteString(" -> ")
-				}
-				trace.WriteString(fmt.Sprintf("%s.%s", path[i].PkgPath, path[i].TypeName))
+		for i, entry := range path {
+			if entry.Type != t {
+				continue
 			}
-			trace.WriteString(fmt.Sprintf(" -> %s.%s\n\n", t.PkgPath(), t.Name()))
-
-			// Build detailed trace
-			trace.WriteString("Full trace with field/method chain:\n")
-			for i, entry := range path {
-				trace.WriteString(fmt.Sprintf("  %d: %s.%s", i, entry.PkgPath, entry.TypeName))
-				if entry.File != "" {
-					trace.WriteString(fmt.Sprintf(" (%s:%d)", entry.File, entry.Line))
-				}
-				if entry.Field != "" {
-					trace.WriteString(fmt.Sprintf(" via field %q", entry.Field))
+			cycle := path[i:]
+			for _, step := range cycle {
+				if isReferencedType(reflector, step.Type, root) {
+					return nil
 				}
-				if entry.Method != "" {
-					trace.WriteString(fmt.Sprintf(" via method %q", entry.Method))
+			}
+			return cycleError(cycle, t)
+		}
+		path = append(path, typeTrace{Type: t})
+
+		switch t.Kind() {
+		case reflect.Struct:
+			for i := 0; i < t.NumField(); i++ {
+				field := t.Field(i)
+				if !field.IsExported() && !field.Anonymous {
+					continue
 				}
-				if entry.MapKey != "" {
-					trace.WriteString(fmt.Sprintf(" via map key %q", entry.MapKey))
+				if field.Tag.Get(fieldNameTag) == "-" || field.Tag.Get("jsonschema") == "-" {
+					continue
 				}
-				if entry.SliceIdx >= 0 {
-					trace.WriteString(fmt.Sprintf(" via slice index %d", entry.SliceIdx))
+				path[len(path)-1].Field = field.Name
+				if err := walk(field.Type, path); err != nil {
+					return err
 				}
-				trace.WriteString("\n")
 			}
-			trace.WriteString(fmt.Sprintf("  %d: %s.%s", len(path), t.PkgPath(), t.Name()))
-			if file != "" {
-				trace.WriteString(fmt.Sprintf(" (%s:%d)", file, line))
+		case reflect.Slice, reflect.Array, reflect.Map:
+			path[len(path)-1].Field = ""
+			if err := walk(t.Elem(), path); err != nil {
+				return err
 			}
-			trace.WriteString("\n")
-
-			return fmt.Errorf(trace.String())
 		}
-	}
 
-	// Add current type to path with package info
-	currentTrace := typeTrace{
-		PkgPath:  t.PkgPath(),
-		TypeName: t.Name(),
-		Type:     t,
-		File:     file,
-		Line:     line,
+		checked[t] = true
+		return nil
 	}
-	newPath := append(path, currentTrace)
-
-	// Recursively check struct fields and interface methods
-	switch val.Kind() {
-	case reflect.Struct:
-		t := val.Type() // Get type from the dereferenced value
-		for i := 0; i < val.NumField(); i++ {
-			field := t.Field(i)
-			// Skip unexported fields (both from other packages and same package)
-			if field.PkgPath != "" || !field.IsExported() {
-				continue
-			}
 
-			// Check for embedded cycles before any reflection operations
-			fieldType := field.Type
-			if fieldType.Kind() == reflect.Ptr {
-				fieldType = fieldType.Elem()
-			}
-			// Skip embedded fields of the same type (or pointer to same type) to prevent infinite recursion
-			if field.Anonymous && fieldType == t {
-				continue
-			}
+	return walk(t, nil)
+}
 
-			fieldVal := val.Field(i)
-			fieldTrace := typeTrace{
-				PkgPath:  t.PkgPath(),
-				TypeName: t.Name(),
-				Type:     t,
-				File:     file,
-				Line:     line,
-				Field:    field.Name,
-			}
-			if err := r.checkForCycles(fieldVal.Interface(), append(newPath, fieldTrace)); err != nil {
-				return err
-			}
-		}
-	case reflect.Interface:
-		for i := 0; i < val.NumMethod(); i++ {
-			method := val.Method(i)
-			methodTrace := typeTrace{
-				PkgPath:  t.PkgPath(),
-				TypeName: t.Name(),
-				Type:     t,
-				File:     file,
-				Line:     line,
-				Method:   t.Method(i).Name,
-			}
-			if err := r.checkForCycles(method.Type(), append(newPath, methodTrace)); err != nil {
-				return err
-			}
-		}
-	}
+// cycleError describes a cycle of the type graph which cannot be represented.
+func cycleError(cycle []typeTrace, t reflect.Type) error {
+	var trace strings.Builder
 
-	// Recursively check slice/array elements
-	if val.Kind() == reflect.Slice || val.Kind() == reflect.Array {
-		for i := 0; i < val.Len(); i++ {
-			elemTrace := typeTrace{
-				Type:     t,
-				File:     file,
-				Line:     line,
-				SliceIdx: i,
-			}
-			if err := r.checkForCycles(val.Index(i).Interface(), append(newPath, elemTrace)); err != nil {
-				return err
-			}
-		}
+	// Build cycle summary
+	trace.WriteString("cycle detected in type graph, which can only be represented with named types referenced as components:\n")
+	trace.WriteString("Cycle path summary:\n")
+	for _, entry := range cycle {
+		trace.WriteString(fmt.Sprintf("%s -> ", entry.Type))
 	}
-
-	// Recursively check map values
-	if val.Kind() == reflect.Map {
-		for _, key := range val.MapKeys() {
-			keyTrace := typeTrace{
-				Type:   t,
-				File:   file,
-				Line:   line,
-				MapKey: fmt.Sprintf("%v", key.Interface()),
-			}
-			if err := r.checkForCycles(val.MapIndex(key).Interface(), append(newPath, keyTrace)); err != nil {
-				return err
-			}
+	trace.WriteString(fmt.Sprintf("%s\n\n", t))
+
+	// Build detailed trace
+	trace.WriteString("Full trace with field chain:\n")
+	for i, entry := range cycle {
+		trace.WriteString(fmt.Sprintf("  %d: %s", i, entry.Type))
+		if entry.Field != "" {
+			trace.WriteString(fmt.Sprintf(" via field %q", entry.Field))
+		} else {
+			trace.WriteString(" via element")
 		}
+		trace.WriteString("\n")
 	}
+	trace.WriteString(fmt.Sprintf("  %d: %s\n", len(cycle), t))
 
-	// Note: Generic type parameters are not directly accessible via reflection
-	// The cycle detection for regular fields above will catch cycles in generic types
-
-	return nil
+	return errors.New(trace.String())
 }
 
 func (r Router[_, _, _]) getSchemaFromInterface(v any, allowAdditionalProperties bool) (*openapi3.SchemaRef, error) {
+	return r.getDirectedSchemaFromInterface(v, allowAdditionalProperties, responseDirection)
+}
+
+// getDirectedSchemaFromInterface returns the schema of v for data sent in
+// direction, omitting the fields tagged readOnly in requests and writeOnly in responses.
+func (r Router[_, _, _]) getDirectedSchemaFromInterface(v any, allowAdditionalProperties bool, direction schemaDirection) (*openapi3.SchemaRef, error) {
 	if v == nil {
 		return &openapi3.SchemaRef{}, nil
 	}
 
-	// First check for cycles in the type graph
-	if err := r.checkForCycles(v, nil); err != nil {
-		return nil, fmt.Errorf("invalid schema: %w", err)
-	}
-
-	// If we detect any embedded struct, abort immediately as it's potentially cyclic
-	val := reflect.ValueOf(v)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
-	}
-	if val.Kind() == reflect.Struct {
-		for i := 0; i < val.NumField(); i++ {
-			field := val.Type().Field(i)
-			if field.Anonymous {
-				return nil, fmt.Errorf("invalid schema: embedded struct %s detected - potential infinite recursion", field.Type)
-			}
-		}
-	}
-
 	reflector := &jsonschema.Reflector{
 		DoNotReference:            false,
 		AllowAdditionalProperties: allowAdditionalProperties,
@@ -646,10 +797,71 @@ func (r Router[_, _, _]) getSchemaFromInterface(v any, allowAdditionalProperties
 			RequiredFromJSONSchemaTags: r.reflectorOptions.RequiredFromJSONSchemaTags,
 		}
 	}
+	reflector.Mapper = typeMapper(reflector.Mapper, r.options.typeMappings)
+	naming := newComponentNaming(r.componentTypes, reflector.Namer, r.options.componentNamer)
+	reflector.Namer = naming.name
+
+	types := []reflect.Type{reflect.TypeOf(v)}
+	polymorphic, isPolymorphic := v.(*Polymorphic)
+	if isPolymorphic {
+		if err := polymorphic.validate(); err != nil {
+			return nil, fmt.Errorf("invalid schema: %w", err)
+		}
+		types = types[:0]
+		for _, variant := range polymorphic.variants {
+			types = append(types, reflect.TypeOf(variant))
+		}
+	}
+	for _, t := range types {
+		// Embedded structs are only rejected when they embed themselves
+		if err := checkEmbeddedCycles(t, reflectorFieldNameTag(reflector)); err != nil {
+			return nil, fmt.Errorf("invalid schema: %w", err)
+		}
+
+		// Recursive types are referenced as components, other cycles are rejected
+		if err := checkForCycles(t, reflector); err != nil {
+			return nil, fmt.Errorf("invalid schema: %w", err)
+		}
+	}
+
+	// Structs embedding other structs are reflected with their promoted fields,
+	// and validator tags are translated into constraints. The root struct
+	// is expanded afterwards, since it may be one of them.
+	embeddedDefinitions := jsonschema.Definitions{}
+	expandedStruct := reflector.ExpandedStruct
+	reflector.ExpandedStruct = false
+	reflector.Mapper = enumMapper(reflector.Mapper, r.enums)
+	reflector.Mapper = interfaceMapper(reflector, embeddedDefinitions, r.interfaces)
+	reflector.Mapper = structMapper(reflector, embeddedDefinitions, r.options.validatorTag, direction)
 
 	// Reflect the Go type into a jsonschema.Schema
-	jsonSchema := reflector.Reflect(v)
+	var jsonSchema *jsonschema.Schema
+	if isPolymorphic {
+		jsonSchema = polymorphicSchema(reflector, embeddedDefinitions, polymorphic)
+	} else {
+		jsonSchema = reflector.Reflect(v)
+	}
+	if naming.err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", naming.err)
+	}
 	jsonSchema.Version = ""
+	for name, def := range embeddedDefinitions {
+		if jsonSchema.Definitions == nil {
+			jsonSchema.Definitions = jsonschema.Definitions{}
+		}
+		if _, exists := jsonSchema.Definitions[name]; !exists {
+			jsonSchema.Definitions[name] = def
+		}
+	}
+	if expandedStruct {
+		name := strings.TrimPrefix(jsonSchema.Ref, "#/$defs/")
+		if def, ok := jsonSchema.Definitions[name]; ok && jsonSchema.Ref != "" {
+			definitions := jsonSchema.Definitions
+			delete(definitions, name)
+			*jsonSchema = *def
+			jsonSchema.Definitions = definitions
+		}
+	}
 
 	// Handle definitions first - this is where we store the full schema
 	if len(jsonSchema.Definitions) > 0 {
@@ -674,6 +886,10 @@ func (r Router[_, _, _]) getSchemaFromInterface(v any, allowAdditionalProperties
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal jsonschema definition %q: %w", name, err)
 			}
+			defData, err = normalizeReflectedSchema(defData, isOpenapi31(r.swaggerSchema))
+			if err != nil {
+				return nil, fmt.Errorf("failed to normalize jsonschema definition %q: %w", name, err)
+			}
 
 			// Unmarshal the JSON definition into an openapi3.Schema
 			oasSchema := openapi3.NewSchema()
@@ -687,11 +903,12 @@ func (r Router[_, _, _]) getSchemaFromInterface(v any, allowAdditionalProperties
 			// Only add if it doesn't exist yet
 			if _, exists := r.swaggerSchema.Components.Schemas[componentName]; !exists {
 				r.swaggerSchema.Components.Schemas[componentName] = &openapi3.SchemaRef{Value: oasSchema}
+				naming.commit(componentName)
 			}
 		}
 	}
 
-	if jsonSchema.Type == "array" && jsonSchema.Definitions != nil {
+	if (jsonSchema.Type == "array" || isPolymorphic) && jsonSchema.Definitions != nil {
 		jsonSchema.Definitions = nil
 	}
 
@@ -705,6 +922,10 @@ func (r Router[_, _, _]) getSchemaFromInterface(v any, allowAdditionalProperties
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal jsonschema: %w", err)
 	}
+	data, err = normalizeReflectedSchema(data, isOpenapi31(r.swaggerSchema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalize jsonschema: %w", err)
+	}
 
 	// Unmarshal the main schema JSON into an openapi3.Schema
 	oasSchema := openapi3.NewSchema()
@@ -717,7 +938,20 @@ func (r Router[_, _, _]) getSchemaFromInterface(v any, allowAdditionalProperties
 	return openapi3.NewSchemaRef("", oasSchema), nil
 }
 
-func (r Router[_, _, _]) addContentToOASSchema(content Content) (openapi3.Content, error) {
+// newSchemaRef returns the schema of the value of schema for data sent in
+// direction, or a reference to its component if it is registered.
+func (r Router[_, _, _]) newSchemaRef(schema Schema, direction schemaDirection) (*openapi3.SchemaRef, error) {
+	if schema.Ref != "" {
+		component, err := r.schemaComponent(schema.Ref)
+		if err != nil {
+			return nil, err
+		}
+		return &openapi3.SchemaRef{Ref: componentRef("schemas", schema.Ref), Value: component.Value}, nil
+	}
+	return r.getDirectedSchemaFromInterface(schema.Value, schema.AllowAdditionalProperties, direction)
+}
+
+func (r Router[_, _, _]) addContentToOASSchema(content Content, examples Examples, direction schemaDirection) (openapi3.Content, error) {
 	oasContent := openapi3.NewContent()
 	// Sort content types for consistent order
 	var mediaTypes []string
@@ -728,12 +962,16 @@ func (r Router[_, _, _]) addContentToOASSchema(content Content) (openapi3.Conten
 
 	for _, k := range mediaTypes {
 		v := content[k]
-		var err error
-		schema, err := r.getSchemaFromInterface(v.Value, v.AllowAdditionalProperties)
+		schema, err := r.newSchemaRef(v, direction)
+		if err != nil {
+			return nil, err
+		}
+		mediaType := openapi3.NewMediaType().WithSchemaRef(schema)
+		mediaType.Examples, err = newOASExamples(examples, v.Examples)
 		if err != nil {
 			return nil, err
 		}
-		oasContent[k] = openapi3.NewMediaType().WithSchemaRef(schema)
+		oasContent[k] = mediaType
 	}
 	return oasContent, nil
 }
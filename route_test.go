@@ -8,6 +8,7 @@ import (
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gorilla/mux"
+	"github.com/invopop/jsonschema"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.lumeweb.com/gswagger/support/gorilla"
@@ -711,6 +712,30 @@ func TestAddRoutes(t *testing.T) {
 			testPath:     "/users",
 			fixturesPath: "testdata/security.json",
 		},
+		{
+			name: "schema with recursive types",
+			routes: func(t *testing.T, router *TestRouter) {
+				route, err := router.AddRoute(http.MethodPost, "/comments", okHandler, Definitions{
+					RequestBody: &ContentValue{
+						Content: Content{
+							jsonType: {Value: Comment{}},
+						},
+					},
+					Responses: map[int]ContentValue{
+						200: {
+							Content: Content{
+								jsonType: {Value: []Employee{}},
+							},
+						},
+					},
+				})
+				require.NoError(t, err)
+				require.NotNil(t, route)
+			},
+			testPath:     "/comments",
+			testMethod:   http.MethodPost,
+			fixturesPath: "testdata/recursive.json",
+		},
 		{
 			name: "schema with extension",
 			routes: func(t *testing.T, router *TestRouter) {
@@ -1160,6 +1185,28 @@ type Child struct {
 	Parent *Parent
 }
 
+type Comment struct {
+	Text    string    `json:"text"`
+	Replies []Comment `json:"replies,omitempty"`
+}
+
+type Employee struct {
+	Name    string    `json:"name"`
+	Manager *Employee `json:"manager,omitempty"`
+	Team    *Team     `json:"team,omitempty"`
+}
+type Team struct {
+	Members []Employee `json:"members"`
+}
+
+type EmbeddingA struct {
+	*EmbeddingB
+	Name string
+}
+type EmbeddingB struct {
+	*EmbeddingA
+}
+
 type User struct {
 	Name   string
 	Groups []*Group
@@ -1220,12 +1267,12 @@ func TestCycleDetection(t *testing.T) {
 				n.Next = n
 				return n
 			}(),
-			expectError: true,
+			expectError: false,
 		},
 		{
 			name:        "nested cycle",
 			input:       &Parent{Child: &Child{Parent: &Parent{}}},
-			expectError: true,
+			expectError: false,
 		},
 		{
 			name: "complex cycle with slices",
@@ -1238,7 +1285,7 @@ func TestCycleDetection(t *testing.T) {
 					},
 				},
 			},
-			expectError: true,
+			expectError: false,
 		},
 		{
 			name: "indirect cycle through multiple types",
@@ -1249,7 +1296,7 @@ func TestCycleDetection(t *testing.T) {
 					},
 				},
 			},
-			expectError: true,
+			expectError: false,
 		},
 		{
 			name: "primitive types don't trigger cycles",
@@ -1277,21 +1324,26 @@ func TestCycleDetection(t *testing.T) {
 				Exported: &MixedFields{},
 				hidden:   &MixedFields{},
 			},
-			expectError: true, // Should error since there's a cycle through Exported
+			expectError: false, // Recursive named types are referenced as components
 		},
 		{
 			name:          "embedded cycle",
 			input:         &EmbeddedCycle{},
 			expectError:   true,
-			errorContains: "embedded struct *swagger.EmbeddedCycle detected - potential infinite recursion",
+			errorContains: "embedded struct *swagger.EmbeddedCycle creates a cycle: swagger.EmbeddedCycle -> swagger.EmbeddedCycle",
 		},
 		{
-			name: "indirect embedded cycle",
+			name:          "indirect embedded cycle",
+			input:         &EmbeddingA{},
+			expectError:   true,
+			errorContains: "embedded struct *swagger.EmbeddingA creates a cycle: swagger.EmbeddingA -> swagger.EmbeddingB -> swagger.EmbeddingA",
+		},
+		{
+			name: "embedded type with recursive fields",
 			input: &struct {
-				*Parent // Embedded type that will cycle
+				*Parent
 			}{},
-			expectError:   true,
-			errorContains: "embedded struct *swagger.Parent detected - potential infinite recursion",
+			expectError: false,
 		},
 		{
 			name: "indirect unexported cycle",
@@ -1313,7 +1365,7 @@ func TestCycleDetection(t *testing.T) {
 					},
 				},
 			},
-			expectError: true,
+			expectError: false,
 		},
 	}
 
@@ -1336,6 +1388,85 @@ func TestCycleDetection(t *testing.T) {
 	}
 }
 
+func TestRecursiveTypesWithoutReferences(t *testing.T) {
+	router, err := NewRouter(gorilla.NewRouter(mux.NewRouter()), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+		Openapi:          getBaseSwagger(t),
+		ReflectorOptions: &jsonschema.Reflector{DoNotReference: true},
+	})
+	require.NoError(t, err)
+
+	_, err = router.getSchemaFromInterface(Team{}, false)
+	require.EqualError(t, err, `invalid schema: cycle detected in type graph, which can only be represented with named types referenced as components:
+Cycle path summary:
+swagger.Employee -> swagger.Employee
+
+Full trace with field chain:
+  0: swagger.Employee via field "Manager"
+  1: swagger.Employee
+`)
+
+	_, err = router.getSchemaFromInterface(Comment{}, false)
+	require.Error(t, err)
+
+	_, err = router.getSchemaFromInterface(struct{ Name string }{}, false)
+	require.NoError(t, err)
+}
+
+func TestParameterErrors(t *testing.T) {
+	definitions := Definitions{
+		Querystring: ParameterValue{
+			"filter": {Schema: &Schema{Value: EmbeddingA{}}},
+			"limit":  {Schema: &Schema{Value: 0}},
+		},
+		Headers: ParameterValue{
+			"X-Filter": {Content: Content{jsonType: {Value: &EmbeddingB{}}}},
+		},
+	}
+
+	setup := func(t *testing.T, strict bool, warnings *[]error) *TestRouter {
+		t.Helper()
+
+		router, err := NewRouter(gorilla.NewRouter(mux.NewRouter()), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Openapi: getBaseSwagger(t),
+			Strict:  strict,
+			WarningHandler: func(err error) {
+				*warnings = append(*warnings, err)
+			},
+		})
+		require.NoError(t, err)
+		return router
+	}
+
+	t.Run("strict mode returns every parameter error", func(t *testing.T) {
+		var warnings []error
+		router := setup(t, true, &warnings)
+
+		_, err := router.AddRoute(http.MethodGet, "/users", okHandler, definitions)
+		require.ErrorIs(t, err, ErrQuerystring)
+		require.ErrorIs(t, err, ErrHeaders)
+		require.NotErrorIs(t, err, ErrPathParams)
+		require.Contains(t, err.Error(), `errors generating querystring schema for GET /users: parameter "filter": invalid schema: embedded struct`)
+		require.Contains(t, err.Error(), `errors generating headers schema for GET /users: parameter "X-Filter": invalid schema: embedded struct`)
+		require.Empty(t, warnings)
+		require.Nil(t, router.GetSwaggerSchema().Paths.Value("/users"))
+	})
+
+	t.Run("dropped parameters are reported as warnings", func(t *testing.T) {
+		var warnings []error
+		router := setup(t, false, &warnings)
+
+		_, err := router.AddRoute(http.MethodGet, "/users", okHandler, definitions)
+		require.NoError(t, err)
+		require.Len(t, warnings, 2)
+		require.ErrorIs(t, warnings[0], ErrQuerystring)
+		require.ErrorIs(t, warnings[1], ErrHeaders)
+
+		parameters := router.GetSwaggerSchema().Paths.Value("/users").Get.Parameters
+		require.Len(t, parameters, 1)
+		require.Equal(t, "limit", parameters[0].Value.Name)
+	})
+}
+
 func TestGetPathParamsAutoComplete(t *testing.T) {
 	testCases := map[string]struct {
 		schemaDefinition Definitions
@@ -0,0 +1,80 @@
+package swagger
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+)
+
+// RouteInfo describes a route added to a router.
+type RouteInfo struct {
+	Method          string // HTTP method
+	Path            string // Path of the route in the framework router, with the prefix of its router
+	OASPath         string // Path of the route in the OpenAPI schema
+	Host            string // Host of the host router, empty for the routes of the root router
+	Prefix          string // Path prefix of the router the route was added to, including Options.PathPrefix
+	OperationID     string // Operation identifier
+	Handler         string // Name of the handler function
+	MiddlewareCount int    // Number of middleware passed when adding the route
+}
+
+// addedRoute is a route recorded by the root router, with the router it was
+// added to.
+type addedRoute[HandlerFunc, MiddlewareFunc, Route any] struct {
+	info   RouteInfo
+	router *Router[HandlerFunc, MiddlewareFunc, Route]
+}
+
+// Routes returns the routes added with AddRoute, AddRawRoute or AddTypedRoute,
+// in the order they were added. The root router returns the routes of every
+// group and host router, while a group or a host router returns its own
+// routes and the routes of the groups created from it.
+func (r *Router[HandlerFunc, MiddlewareFunc, Route]) Routes() []RouteInfo {
+	root := r.rootRouter
+	if root == nil {
+		root = r
+	}
+
+	routes := make([]RouteInfo, 0, len(root.routes))
+	for _, route := range root.routes {
+		if !route.router.createdFrom(r) {
+			continue
+		}
+		routes = append(routes, route.info)
+	}
+	return routes
+}
+
+// addRouteInfo records a route added to the router.
+func (r *Router[HandlerFunc, MiddlewareFunc, Route]) addRouteInfo(route RouteInfo) {
+	root := r.rootRouter
+	if root == nil {
+		root = r
+	}
+	route.Host = r.host
+	route.Prefix = r.pathPrefix
+	root.routes = append(root.routes, addedRoute[HandlerFunc, MiddlewareFunc, Route]{info: route, router: r})
+}
+
+// createdFrom reports whether the router is router, or a group or a host
+// router created from it, directly or through other groups.
+func (r *Router[HandlerFunc, MiddlewareFunc, Route]) createdFrom(router *Router[HandlerFunc, MiddlewareFunc, Route]) bool {
+	for current := r; current != nil; current = current.parent {
+		if current == router {
+			return true
+		}
+	}
+	return false
+}
+
+// handlerName returns the name of the function handler, or its type if it is
+// not a function.
+func handlerName(handler any) string {
+	v := reflect.ValueOf(handler)
+	if v.Kind() == reflect.Func && !v.IsNil() {
+		if fn := runtime.FuncForPC(v.Pointer()); fn != nil {
+			return fn.Name()
+		}
+	}
+	return fmt.Sprintf("%T", handler)
+}
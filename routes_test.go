@@ -0,0 +1,133 @@
+package swagger
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.lumeweb.com/gswagger/support/gorilla"
+)
+
+func listNotes(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func getNote(_ context.Context, _ struct{}) (typedUser, error) {
+	return typedUser{}, nil
+}
+
+func TestRoutes(t *testing.T) {
+	_, router := setupGorillaHostRouterTest(t)
+	noop := mux.MiddlewareFunc(func(next http.Handler) http.Handler { return next })
+
+	_, err := router.AddRoute(http.MethodGet, "/notes", listNotes, Definitions{OperationID: "listNotes"})
+	require.NoError(t, err)
+
+	admin, err := router.Group("/admin")
+	require.NoError(t, err)
+	_, err = admin.AddRoute(http.MethodDelete, "/notes/{id}", okHandler, Definitions{OperationID: "deleteNote"}, noop, noop)
+	require.NoError(t, err)
+
+	reports, err := admin.Group("/reports")
+	require.NoError(t, err)
+	_, err = reports.AddRawRoute(http.MethodGet, "/daily", okHandler, Operation{})
+	require.NoError(t, err)
+
+	host, err := router.Host("api.example.com")
+	require.NoError(t, err)
+	_, err = host.AddRoute(http.MethodGet, "/items", okHandler, Definitions{}, noop)
+	require.NoError(t, err)
+
+	t.Run("the root router lists every route", func(t *testing.T) {
+		routes := router.Routes()
+		require.Len(t, routes, 4)
+
+		require.Equal(t, RouteInfo{
+			Method:      http.MethodGet,
+			Path:        "/notes",
+			OASPath:     "/notes",
+			OperationID: "listNotes",
+			Handler:     "go.lumeweb.com/gswagger.listNotes",
+		}, routes[0])
+
+		require.Equal(t, http.MethodDelete, routes[1].Method)
+		require.Equal(t, "/admin/notes/{id}", routes[1].Path)
+		require.Equal(t, "/admin/notes/{id}", routes[1].OASPath)
+		require.Equal(t, "/admin", routes[1].Prefix)
+		require.Equal(t, "deleteNote", routes[1].OperationID)
+		require.Equal(t, 2, routes[1].MiddlewareCount)
+
+		require.Equal(t, "/admin/reports/daily", routes[2].Path)
+		require.Equal(t, "/admin/reports", routes[2].Prefix)
+
+		require.Equal(t, "api.example.com", routes[3].Host)
+		require.Equal(t, "/items", routes[3].Path)
+		require.Equal(t, 1, routes[3].MiddlewareCount)
+	})
+
+	t.Run("groups and host routers list their routes", func(t *testing.T) {
+		routes := admin.Routes()
+		require.Len(t, routes, 2)
+		require.Equal(t, "/admin/notes/{id}", routes[0].Path)
+		require.Equal(t, "/admin/reports/daily", routes[1].Path)
+
+		routes = reports.Routes()
+		require.Len(t, routes, 1)
+		require.Equal(t, "/admin/reports/daily", routes[0].Path)
+
+		routes = host.Routes()
+		require.Len(t, routes, 1)
+		require.Equal(t, "/items", routes[0].Path)
+	})
+	t.Run("sibling groups with the same prefix list their own routes", func(t *testing.T) {
+		_, router := setupGorillaHostRouterTest(t)
+
+		public, err := router.Group("/api")
+		require.NoError(t, err)
+		_, err = public.AddRoute(http.MethodGet, "/notes", okHandler, Definitions{})
+		require.NoError(t, err)
+
+		internal, err := router.Group("/api")
+		require.NoError(t, err)
+		_, err = internal.AddRoute(http.MethodGet, "/metrics", okHandler, Definitions{})
+		require.NoError(t, err)
+
+		routes := public.Routes()
+		require.Len(t, routes, 1)
+		require.Equal(t, "/api/notes", routes[0].Path)
+
+		routes = internal.Routes()
+		require.Len(t, routes, 1)
+		require.Equal(t, "/api/metrics", routes[0].Path)
+
+		require.Len(t, router.Routes(), 2)
+	})
+
+	t.Run("the prefix includes the path prefix of the router", func(t *testing.T) {
+		router, err := NewRouter(gorilla.NewRouter(mux.NewRouter()), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Openapi:    getBaseSwagger(t),
+			PathPrefix: "/v1",
+		})
+		require.NoError(t, err)
+		_, err = router.AddRoute(http.MethodGet, "/notes", okHandler, Definitions{})
+		require.NoError(t, err)
+
+		routes := router.Routes()
+		require.Len(t, routes, 1)
+		require.Equal(t, "/v1/notes", routes[0].Path)
+		require.Equal(t, "/v1", routes[0].Prefix)
+	})
+
+	t.Run("typed routes record the typed handler", func(t *testing.T) {
+		_, router := setupGorillaHostRouterTest(t)
+
+		_, err := AddTypedRoute(router, http.MethodGet, "/notes/{id}", getNote, Definitions{})
+		require.NoError(t, err)
+
+		routes := router.Routes()
+		require.Len(t, routes, 1)
+		require.Equal(t, "go.lumeweb.com/gswagger.getNote", routes[0].Handler)
+	})
+}
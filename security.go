@@ -0,0 +1,232 @@
+package swagger
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.lumeweb.com/gswagger/apirouter"
+)
+
+// ErrSecurityScheme is returned when a security scheme is invalid, or when a
+// security requirement references a scheme which is not defined.
+var ErrSecurityScheme = errors.New("invalid security scheme")
+
+// Authenticator checks that a request satisfies a security scheme,
+// with the scopes required by the security requirement.
+// Errors implementing apirouter.HTTPError are sent to the client as they are,
+// other errors are reported as a 401 AuthenticationError.
+type Authenticator func(req *http.Request, scopes []string) error
+
+// AuthenticationError is returned to the client when a request does not
+// satisfy the security requirements of its route.
+type AuthenticationError struct {
+	Message string `json:"message"`
+	Scheme  string `json:"scheme"`
+	Err     error  `json:"-"`
+}
+
+func (e *AuthenticationError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%s for %s", e.Message, e.Scheme)
+	}
+	return fmt.Sprintf("%s for %s: %s", e.Message, e.Scheme, e.Err)
+}
+
+func (e *AuthenticationError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode implements apirouter.HTTPError.
+func (e *AuthenticationError) StatusCode() int {
+	return http.StatusUnauthorized
+}
+
+// RegisterSecurityScheme adds a security scheme to the components of the
+// schema of the router, so that it can be referenced by name in the security
+// requirements of the routes.
+//
+// If authenticator is not nil, it is run before the handler of the routes
+// which require the scheme, including the routes added before, and the
+// framework router must implement apirouter.ValidatingRouter. Groups share the schemes of their
+// parent, while host routers have their own.
+func (r *Router[_, MiddlewareFunc, _]) RegisterSecurityScheme(name string, scheme *openapi3.SecurityScheme, authenticator Authenticator) error {
+	if name == "" || scheme == nil {
+		return fmt.Errorf("%w: name and scheme are required", ErrSecurityScheme)
+	}
+	if err := scheme.Validate(r.context); err != nil {
+		return fmt.Errorf("%w %q: %s", ErrSecurityScheme, name, err)
+	}
+	if authenticator != nil {
+		if _, ok := r.router.(apirouter.ValidatingRouter[MiddlewareFunc]); !ok {
+			return ErrValidationNotSupported
+		}
+	}
+
+	if r.swaggerSchema.Components == nil {
+		r.swaggerSchema.Components = &openapi3.Components{}
+	}
+	if r.swaggerSchema.Components.SecuritySchemes == nil {
+		r.swaggerSchema.Components.SecuritySchemes = make(openapi3.SecuritySchemes)
+	}
+	if _, exists := r.swaggerSchema.Components.SecuritySchemes[name]; exists {
+		return fmt.Errorf("%w %q: already registered", ErrSecurityScheme, name)
+	}
+	r.swaggerSchema.Components.SecuritySchemes[name] = &openapi3.SecuritySchemeRef{Value: scheme}
+
+	if authenticator != nil {
+		r.authenticators[name] = authenticator
+	}
+	return nil
+}
+
+// newAuthenticator returns an apirouter.RequestValidator enforcing the
+// security requirements of the operation, or of the schema if the operation
+// has none. Requirements are alternatives, while all the schemes of a
+// requirement must be satisfied. Authenticators are looked up when the
+// request is received, so that the schemes registered after the route are
+// enforced too.
+//
+// Requirements referencing no scheme with an authenticator are only
+// documented. Otherwise authentication fails closed: a requirement is
+// satisfied only if every scheme has an authenticator which succeeds.
+func (r *Router[_, _, _]) newAuthenticator(operation *openapi3.Operation) apirouter.RequestValidator {
+	return func(req *http.Request, _ map[string]string) error {
+		requirements := r.swaggerSchema.Security
+		if operation.Security != nil {
+			requirements = *operation.Security
+		}
+		if !r.enforcesSecurity(requirements) {
+			return nil
+		}
+
+		var authErr, unenforcedErr error
+		for _, requirement := range requirements {
+			err := r.authenticate(req, requirement)
+			if err == nil {
+				return nil
+			}
+			var authenticationErr *AuthenticationError
+			if errors.As(err, &authenticationErr) && authenticationErr.Message == unenforcedSchemeMessage {
+				unenforcedErr = err
+				continue
+			}
+			authErr = err
+		}
+		if authErr != nil {
+			return authErr
+		}
+		return unenforcedErr
+	}
+}
+
+// unenforcedSchemeMessage is the message of the AuthenticationError of a
+// scheme without authenticator, in a requirement which must be enforced.
+const unenforcedSchemeMessage = "no authenticator"
+
+// enforcesSecurity reports whether a scheme of requirements has an authenticator.
+func (r *Router[_, _, _]) enforcesSecurity(requirements openapi3.SecurityRequirements) bool {
+	for _, requirement := range requirements {
+		for name := range requirement {
+			if _, ok := r.authenticators[name]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// authenticate runs the authenticators of the schemes of a requirement,
+// in name order. A scheme without authenticator is not satisfied.
+func (r *Router[_, _, _]) authenticate(req *http.Request, requirement openapi3.SecurityRequirement) error {
+	names := make([]string, 0, len(requirement))
+	for name := range requirement {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		authenticator, ok := r.authenticators[name]
+		if !ok {
+			return &AuthenticationError{
+				Message: unenforcedSchemeMessage,
+				Scheme:  name,
+			}
+		}
+		if err := authenticator(req, requirement[name]); err != nil {
+			var httpErr apirouter.HTTPError
+			if errors.As(err, &httpErr) {
+				return err
+			}
+			return &AuthenticationError{
+				Message: "authentication failed",
+				Scheme:  name,
+				Err:     err,
+			}
+		}
+	}
+	return nil
+}
+
+// hasSecurityRequirements reports whether the operation, or the schema if
+// the operation has none, has security requirements.
+func hasSecurityRequirements(schema *openapi3.T, operation *openapi3.Operation) bool {
+	if operation.Security != nil {
+		return len(*operation.Security) > 0
+	}
+	return len(schema.Security) > 0
+}
+
+// validateSecurityRequirements returns an error for each security scheme
+// referenced by the requirements of the schema or of its operations which is
+// not defined.
+func validateSecurityRequirements(schema *openapi3.T) []error {
+	var schemes openapi3.SecuritySchemes
+	if schema.Components != nil {
+		schemes = schema.Components.SecuritySchemes
+	}
+
+	var errs []error
+	check := func(requirements openapi3.SecurityRequirements, requiredBy string) {
+		var names []string
+		for _, requirement := range requirements {
+			for name := range requirement {
+				if _, ok := schemes[name]; !ok {
+					names = append(names, name)
+				}
+			}
+		}
+		sort.Strings(names)
+		for i, name := range names {
+			if i > 0 && names[i-1] == name {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("%w: %q required by %s is not defined", ErrSecurityScheme, name, requiredBy))
+		}
+	}
+
+	check(schema.Security, "the schema")
+	if schema.Paths == nil {
+		return errs
+	}
+
+	paths := schema.Paths.InMatchingOrder()
+	sort.Strings(paths)
+	for _, oasPath := range paths {
+		operations := schema.Paths.Value(oasPath).Operations()
+		methods := make([]string, 0, len(operations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			if security := operations[method].Security; security != nil {
+				check(*security, method+" "+oasPath)
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,273 @@
+package swagger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.lumeweb.com/gswagger/support/gorilla"
+)
+
+type forbiddenError struct {
+	Reason string `json:"reason"`
+}
+
+func (e forbiddenError) Error() string   { return e.Reason }
+func (e forbiddenError) StatusCode() int { return http.StatusForbidden }
+
+func TestRegisterSecurityScheme(t *testing.T) {
+	bearer := openapi3.NewJWTSecurityScheme()
+
+	t.Run("adds the scheme to the components", func(t *testing.T) {
+		router := setupRouter(t)
+
+		require.NoError(t, router.RegisterSecurityScheme("bearer", bearer, nil))
+		require.NoError(t, router.RegisterSecurityScheme("oidc", openapi3.NewOIDCSecurityScheme("https://example.com/.well-known/openid-configuration"), nil))
+
+		schemes := router.GetSwaggerSchema().Components.SecuritySchemes
+		require.Equal(t, bearer, schemes["bearer"].Value)
+		require.Contains(t, schemes, "oidc")
+	})
+
+	t.Run("invalid scheme", func(t *testing.T) {
+		router := setupRouter(t)
+
+		err := router.RegisterSecurityScheme("broken", openapi3.NewSecurityScheme().WithType("apiKey"), nil)
+		require.ErrorIs(t, err, ErrSecurityScheme)
+
+		err = router.RegisterSecurityScheme("", bearer, nil)
+		require.ErrorIs(t, err, ErrSecurityScheme)
+	})
+
+	t.Run("duplicate scheme", func(t *testing.T) {
+		router := setupRouter(t)
+
+		require.NoError(t, router.RegisterSecurityScheme("bearer", bearer, nil))
+		err := router.RegisterSecurityScheme("bearer", bearer, nil)
+		require.EqualError(t, err, `invalid security scheme "bearer": already registered`)
+	})
+
+	t.Run("authenticator requires a validating router", func(t *testing.T) {
+		router, err := NewRouter[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route](notValidatingRouter{gorilla.NewRouter(mux.NewRouter())}, Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Context: context.Background(),
+			Openapi: getBaseSwagger(t),
+		})
+		require.NoError(t, err)
+
+		err = router.RegisterSecurityScheme("bearer", bearer, func(*http.Request, []string) error { return nil })
+		require.ErrorIs(t, err, ErrValidationNotSupported)
+	})
+
+	t.Run("undefined schemes are reported", func(t *testing.T) {
+		var warnings []string
+		router, err := NewRouter(gorilla.NewRouter(mux.NewRouter()), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Context: context.Background(),
+			Openapi: getBaseSwagger(t),
+			WarningHandler: func(err error) {
+				require.ErrorIs(t, err, ErrSecurityScheme)
+				warnings = append(warnings, err.Error())
+			},
+		})
+		require.NoError(t, err)
+		router.GetSwaggerSchema().Security = *openapi3.NewSecurityRequirements().With(openapi3.NewSecurityRequirement().Authenticate("global"))
+
+		_, err = router.AddRoute(http.MethodGet, "/users", okHandler, Definitions{
+			Security: SecurityRequirements{{"api_key": nil}},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+		require.Equal(t, []string{
+			`invalid security scheme: "global" required by the schema is not defined`,
+			`invalid security scheme: "api_key" required by GET /users is not defined`,
+		}, warnings)
+	})
+
+	t.Run("undefined schemes are rejected in strict mode", func(t *testing.T) {
+		router, err := NewRouter(gorilla.NewRouter(mux.NewRouter()), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Context: context.Background(),
+			Openapi: getBaseSwagger(t),
+			Strict:  true,
+		})
+		require.NoError(t, err)
+		router.GetSwaggerSchema().Security = *openapi3.NewSecurityRequirements().With(openapi3.NewSecurityRequirement().Authenticate("global"))
+
+		require.NoError(t, router.RegisterSecurityScheme("bearer", bearer, nil))
+		_, err = router.AddRoute(http.MethodGet, "/users", okHandler, Definitions{
+			Security: SecurityRequirements{{"bearer": nil}, {"api_key": nil}},
+		})
+		require.NoError(t, err)
+
+		err = router.GenerateAndExposeOpenapi()
+		require.ErrorIs(t, err, ErrGenerateOAS)
+		require.ErrorIs(t, err, ErrSecurityScheme)
+		require.EqualError(t, err, `fail to generate openapi for root: invalid security scheme: "global" required by the schema is not defined
+invalid security scheme: "api_key" required by GET /users is not defined`)
+	})
+}
+
+func TestAuthenticator(t *testing.T) {
+	muxRouter := mux.NewRouter()
+	router, err := NewRouter(gorilla.NewRouter(muxRouter), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+		Context: context.Background(),
+		Openapi: getBaseSwagger(t),
+	})
+	require.NoError(t, err)
+
+	var scopes []string
+	err = router.RegisterSecurityScheme("bearer", openapi3.NewJWTSecurityScheme(), func(req *http.Request, required []string) error {
+		scopes = required
+		switch req.Header.Get("Authorization") {
+		case "Bearer valid":
+			return nil
+		case "Bearer forbidden":
+			return forbiddenError{Reason: "missing scope"}
+		}
+		return errors.New("invalid token")
+	})
+	require.NoError(t, err)
+	err = router.RegisterSecurityScheme("api_key", openapi3.NewSecurityScheme().WithType("apiKey").WithIn("header").WithName("X-API-Key"), func(req *http.Request, _ []string) error {
+		if req.Header.Get("X-API-Key") != "secret" {
+			return errors.New("invalid api key")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, router.RegisterSecurityScheme("cookie", openapi3.NewCSRFSecurityScheme(), nil))
+
+	_, err = router.AddRoute(http.MethodGet, "/users", okHandler, Definitions{
+		Security: SecurityRequirements{{"bearer": {"users.read"}}, {"api_key": nil}},
+	})
+	require.NoError(t, err)
+	_, err = router.AddRoute(http.MethodGet, "/public", okHandler, Definitions{})
+	require.NoError(t, err)
+	_, err = router.AddRoute(http.MethodGet, "/optional", okHandler, Definitions{
+		Security: SecurityRequirements{{"bearer": nil}, {}},
+	})
+	require.NoError(t, err)
+	_, err = router.AddRoute(http.MethodGet, "/cookie", okHandler, Definitions{
+		Security: SecurityRequirements{{"cookie": nil}},
+	})
+	require.NoError(t, err)
+
+	_, err = router.AddRoute(http.MethodGet, "/mixed", okHandler, Definitions{
+		Security: SecurityRequirements{{"bearer": nil}, {"cookie": nil}},
+	})
+	require.NoError(t, err)
+
+	group, err := router.Group("/v1")
+	require.NoError(t, err)
+	_, err = group.AddRoute(http.MethodGet, "/items", okHandler, Definitions{
+		Security: SecurityRequirements{{"bearer": nil, "api_key": nil}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, router.GenerateAndExposeOpenapi())
+
+	doRequest := func(t *testing.T, target string, headers map[string]string) *http.Response {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		muxRouter.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	t.Run("satisfied requirement reaches the handler", func(t *testing.T) {
+		resp := doRequest(t, "/users", map[string]string{"Authorization": "Bearer valid"})
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, []string{"users.read"}, scopes)
+	})
+
+	t.Run("requirements are alternatives", func(t *testing.T) {
+		resp := doRequest(t, "/users", map[string]string{"X-API-Key": "secret"})
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("unauthenticated request is rejected", func(t *testing.T) {
+		resp := doRequest(t, "/users", nil)
+
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		var authErr AuthenticationError
+		require.NoError(t, json.Unmarshal([]byte(readBody(t, resp.Body)), &authErr))
+		require.Equal(t, AuthenticationError{Message: "authentication failed", Scheme: "api_key"}, authErr)
+	})
+
+	t.Run("http errors are sent as they are", func(t *testing.T) {
+		resp := doRequest(t, "/v1/items", map[string]string{"Authorization": "Bearer forbidden", "X-API-Key": "secret"})
+
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+		require.JSONEq(t, `{"reason":"missing scope"}`, readBody(t, resp.Body))
+	})
+
+	t.Run("all schemes of a requirement are required", func(t *testing.T) {
+		resp := doRequest(t, "/v1/items", map[string]string{"Authorization": "Bearer valid"})
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp = doRequest(t, "/v1/items", map[string]string{"Authorization": "Bearer valid", "X-API-Key": "secret"})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("routes without requirements are not authenticated", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, doRequest(t, "/public", nil).StatusCode)
+	})
+
+	t.Run("empty requirement makes authentication optional", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, doRequest(t, "/optional", nil).StatusCode)
+	})
+
+	t.Run("schemes without authenticator are not enforced", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, doRequest(t, "/cookie", nil).StatusCode)
+	})
+
+	t.Run("requirements mixing schemes without authenticator fail closed", func(t *testing.T) {
+		resp := doRequest(t, "/mixed", nil)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		var authErr AuthenticationError
+		require.NoError(t, json.Unmarshal([]byte(readBody(t, resp.Body)), &authErr))
+		require.Equal(t, AuthenticationError{Message: "authentication failed", Scheme: "bearer"}, authErr)
+
+		require.Equal(t, http.StatusOK, doRequest(t, "/mixed", map[string]string{"Authorization": "Bearer valid"}).StatusCode)
+	})
+}
+
+func TestAuthenticatorRegisteredAfterRoutes(t *testing.T) {
+	muxRouter := mux.NewRouter()
+	router, err := NewRouter(gorilla.NewRouter(muxRouter), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+		Context: context.Background(),
+		Openapi: getBaseSwagger(t),
+	})
+	require.NoError(t, err)
+
+	_, err = router.AddRoute(http.MethodGet, "/users", okHandler, Definitions{
+		Security: SecurityRequirements{{"bearer": nil}},
+	})
+	require.NoError(t, err)
+	err = router.RegisterSecurityScheme("bearer", openapi3.NewJWTSecurityScheme(), func(req *http.Request, _ []string) error {
+		if req.Header.Get("Authorization") != "Bearer valid" {
+			return errors.New("invalid token")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	muxRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+	require.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("Authorization", "Bearer valid")
+	muxRouter.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+}
@@ -0,0 +1,335 @@
+package chi
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.lumeweb.com/gswagger/apirouter"
+)
+
+// HandlerFunc is the http type handler used by chi
+type HandlerFunc func(w http.ResponseWriter, req *http.Request)
+
+// MiddlewareFunc wraps the handler of a route
+type MiddlewareFunc = func(next http.Handler) http.Handler
+
+// Route is the pattern registered on the chi router
+type Route struct {
+	Method  string
+	Pattern string
+}
+
+// wildcardParamName is the name of the path parameter documenting the chi /* wildcard.
+const wildcardParamName = "wildcard"
+
+var _ apirouter.Router[HandlerFunc, MiddlewareFunc, Route] = (*chiRouter)(nil)
+var _ apirouter.ValidatingRouter[MiddlewareFunc] = (*chiRouter)(nil)
+var _ apirouter.ResponseValidatingRouter[MiddlewareFunc] = (*chiRouter)(nil)
+var _ apirouter.BindingRouter[HandlerFunc] = (*chiRouter)(nil)
+
+// hostMux dispatches the requests matching a route of a host router to the
+// sub-mux registered for their host. It runs as a middleware of the router
+// the hosts are created from, so that the requests of other hosts, or not
+// matching the routes of their host, keep being served by that router, with
+// its own routes, catch-all and NotFound handler.
+type hostMux struct {
+	hosts map[string]chi.Router
+	// dispatching reports whether the requests are dispatched to the hosts
+	dispatching bool
+}
+
+// middleware serves the requests matching a host router.
+func (h *hostMux) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !h.serve(w, req) {
+			next.ServeHTTP(w, req)
+		}
+	})
+}
+
+// serve serves req with the sub-mux of its host, and reports whether one of
+// its routes matched.
+func (h *hostMux) serve(w http.ResponseWriter, req *http.Request) bool {
+	router, ok := h.hosts[req.Host]
+	if !ok {
+		if hostname, _, err := net.SplitHostPort(req.Host); err == nil {
+			router, ok = h.hosts[hostname]
+		}
+	}
+	if !ok {
+		return false
+	}
+
+	// Mounted routers match the path left by their parent
+	routePath := req.URL.Path
+	if req.URL.RawPath != "" {
+		routePath = req.URL.RawPath
+	}
+	if rctx := chi.RouteContext(req.Context()); rctx != nil && rctx.RoutePath != "" {
+		routePath = rctx.RoutePath
+	}
+	if !router.Match(chi.NewRouteContext(), req.Method, routePath) {
+		return false
+	}
+
+	hostCtx := chi.NewRouteContext()
+	hostCtx.Routes = router
+	hostCtx.RoutePath = routePath
+	router.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, hostCtx)))
+	return true
+}
+
+// dispatchNotFound serves the requests for the hosts with the NotFound handler
+// of router, for the routers which do not accept h as middleware. The host
+// routers are then only tried for the requests the routes of router do not
+// match, before its previous NotFound handler.
+func (h *hostMux) dispatchNotFound(router chi.Router) {
+	notFound := http.NotFound
+	if mux, ok := router.(interface{ NotFoundHandler() http.HandlerFunc }); ok {
+		notFound = mux.NotFoundHandler()
+	}
+	router.NotFound(func(w http.ResponseWriter, req *http.Request) {
+		if !h.serve(w, req) {
+			notFound(w, req)
+		}
+	})
+	h.dispatching = true
+}
+
+// use adds middleware to router, and reports whether chi accepted it: chi
+// panics when middleware is added after the routes of a router.
+func use(router chi.Router, middleware MiddlewareFunc) (ok bool) {
+	defer func() {
+		ok = recover() == nil
+	}()
+	router.Use(middleware)
+	return true
+}
+
+type chiRouter struct {
+	router chi.Router
+	// mux is the router the host routers are dispatched from: router itself,
+	// or the router an inline group belongs to.
+	mux chi.Router
+	// hostMuxes holds the host dispatcher of each chi router,
+	// shared by all the routers created from the same root.
+	hostMuxes map[chi.Router]*hostMux
+}
+
+// NewRouter returns an apirouter.Router registering routes on a chi router.
+func NewRouter(router chi.Router) apirouter.Router[HandlerFunc, MiddlewareFunc, Route] {
+	return chiRouter{
+		router:    router,
+		mux:       router,
+		hostMuxes: make(map[chi.Router]*hostMux),
+	}
+}
+
+func (r chiRouter) AddRoute(method string, path string, handler HandlerFunc, middleware ...MiddlewareFunc) Route {
+	r.hostMux()
+	router := r.router
+	if len(middleware) > 0 {
+		router = router.With(middleware...)
+	}
+	router.Method(method, path, http.HandlerFunc(handler))
+
+	return Route{Method: method, Pattern: path}
+}
+
+func (r chiRouter) SwaggerHandler(contentType string, blob []byte) HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		w.Write(blob)
+	}
+}
+
+// TransformPathToOasPath converts a chi pattern to an OpenAPI path:
+// the regexp of {name:regexp} params is removed, and the trailing /*
+// wildcard becomes the {wildcard} path parameter.
+func (r chiRouter) TransformPathToOasPath(path string) string {
+	var oasPath strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] != '{' {
+			oasPath.WriteByte(path[i])
+			continue
+		}
+
+		// Find the end of the param, skipping the braces of the regexp
+		end, depth := i, 0
+		for ; end < len(path); end++ {
+			if path[end] == '{' {
+				depth++
+			} else if path[end] == '}' {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+		name := path[i+1 : min(end, len(path))]
+		if colon := strings.Index(name, ":"); colon >= 0 {
+			name = name[:colon]
+		}
+		oasPath.WriteString("{" + name + "}")
+		i = end
+	}
+
+	if strings.HasSuffix(oasPath.String(), "/*") {
+		return strings.TrimSuffix(oasPath.String(), "*") + "{" + wildcardParamName + "}"
+	}
+	return oasPath.String()
+}
+
+func (r chiRouter) Router(_ bool) any {
+	return r.router
+}
+
+// Group returns a router for the routes under pathPrefix, mounted with chi Route.
+// An empty prefix returns an inline group, with its own middleware stack.
+func (r chiRouter) Group(pathPrefix string) apirouter.Router[HandlerFunc, MiddlewareFunc, Route] {
+	r.hostMux()
+	pathPrefix = strings.TrimSuffix(pathPrefix, "/")
+	if pathPrefix == "" {
+		return chiRouter{
+			router:    r.router.Group(nil),
+			mux:       r.mux,
+			hostMuxes: r.hostMuxes,
+		}
+	}
+	if !strings.HasPrefix(pathPrefix, "/") {
+		pathPrefix = "/" + pathPrefix
+	}
+
+	// chi panics when mounting twice on the same path, so reuse the router
+	// already mounted on the prefix.
+	subRouter := r.mountedRouter(pathPrefix)
+	if subRouter == nil {
+		subRouter = r.router.Route(pathPrefix, func(chi.Router) {})
+	}
+	return chiRouter{
+		router:    subRouter,
+		mux:       subRouter,
+		hostMuxes: r.hostMuxes,
+	}
+}
+
+// Host returns a sub-mux serving the requests for host which match its routes,
+// selected by a middleware of the router. The port of host is optional: a host
+// without port matches any port.
+func (r chiRouter) Host(host string) apirouter.Router[HandlerFunc, MiddlewareFunc, Route] {
+	hosts := r.hostMux()
+	if !hosts.dispatching {
+		hosts.dispatchNotFound(r.mux)
+	}
+	router, ok := hosts.hosts[host]
+	if !ok {
+		router = chi.NewRouter()
+		hosts.hosts[host] = router
+	}
+	return chiRouter{
+		router:    router,
+		mux:       router,
+		hostMuxes: r.hostMuxes,
+	}
+}
+
+func (r chiRouter) Use(middleware ...MiddlewareFunc) {
+	r.router.Use(middleware...)
+}
+
+// HasRoute reports whether a route matches req, with the matched chi pattern.
+func (r chiRouter) HasRoute(req *http.Request) (bool, string) {
+	rctx := chi.NewRouteContext()
+	if !r.router.Match(rctx, req.Method, req.URL.Path) {
+		return false, ""
+	}
+	return true, rctx.RoutePattern()
+}
+
+func (r chiRouter) ValidationMiddleware(validate apirouter.RequestValidator) MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if err := validate(req, pathParams(req)); err != nil {
+				status, body := apirouter.ErrorResponse(err)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				w.Write(body)
+				return
+			}
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+func (r chiRouter) ResponseValidationMiddleware(validate apirouter.ResponseValidator) MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			buffer := apirouter.NewResponseBuffer(w)
+			next.ServeHTTP(buffer, req)
+
+			if err := validate(req, pathParams(req), buffer.Status(), w.Header(), buffer.Body()); err != nil {
+				buffer.SendError(err)
+				return
+			}
+			buffer.Send()
+		})
+	}
+}
+
+func (r chiRouter) BindingHandler(handler apirouter.BindingHandlerFunc) HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		handler(w, req, pathParams(req))
+	}
+}
+
+// hostMux returns the host dispatcher of the router, added as middleware
+// before the first route added through r, since chi only accepts middleware
+// then.
+func (r chiRouter) hostMux() *hostMux {
+	hosts, ok := r.hostMuxes[r.mux]
+	if !ok {
+		hosts = &hostMux{hosts: make(map[string]chi.Router)}
+		hosts.dispatching = use(r.mux, hosts.middleware)
+		r.hostMuxes[r.mux] = hosts
+	}
+	return hosts
+}
+
+// mountedRouter returns the chi router mounted on pathPrefix, if any.
+func (r chiRouter) mountedRouter(pathPrefix string) chi.Router {
+	for _, route := range r.router.Routes() {
+		if route.Pattern != pathPrefix+"/*" || route.SubRoutes == nil {
+			continue
+		}
+		if subRouter, ok := route.SubRoutes.(chi.Router); ok {
+			return subRouter
+		}
+	}
+	return nil
+}
+
+// pathParams returns the URL params matched by chi, named as in the
+// OpenAPI path: the * wildcard is returned as wildcard.
+func pathParams(req *http.Request) map[string]string {
+	params := map[string]string{}
+	rctx := chi.RouteContext(req.Context())
+	if rctx == nil {
+		return params
+	}
+	for i, key := range rctx.URLParams.Keys {
+		value := rctx.URLParams.Values[i]
+		if key == "*" {
+			// Mounted routers add an empty * param
+			if value == "" {
+				continue
+			}
+			key = wildcardParamName
+		}
+		params[key] = value
+	}
+	return params
+}
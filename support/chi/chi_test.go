@@ -0,0 +1,274 @@
+package chi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.lumeweb.com/gswagger/apirouter"
+)
+
+func TestChiRouter(t *testing.T) {
+	okHandler := func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}
+
+	t.Run("add route", func(t *testing.T) {
+		mux := chi.NewRouter()
+		ar := NewRouter(mux)
+
+		route := ar.AddRoute(http.MethodGet, "/users/{id}", func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(chi.URLParam(req, "id")))
+		})
+		require.Equal(t, Route{Method: http.MethodGet, Pattern: "/users/{id}"}, route)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "42", w.Body.String())
+
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/42", nil))
+		require.Equal(t, http.StatusMethodNotAllowed, w.Result().StatusCode)
+	})
+
+	t.Run("HasRoute is backed by chi Match", func(t *testing.T) {
+		ar := NewRouter(chi.NewRouter())
+		ar.AddRoute(http.MethodGet, "/files/*", okHandler)
+		ar.Group("/api").AddRoute(http.MethodGet, "/users/{id:[0-9]+}", okHandler)
+
+		found, pattern := ar.HasRoute(httptest.NewRequest(http.MethodGet, "/files/a/b.txt", nil))
+		require.True(t, found)
+		require.Equal(t, "/files/*", pattern)
+
+		found, pattern = ar.HasRoute(httptest.NewRequest(http.MethodGet, "/api/users/1", nil))
+		require.True(t, found)
+		require.Equal(t, "/api/users/{id:[0-9]+}", pattern)
+
+		found, _ = ar.HasRoute(httptest.NewRequest(http.MethodGet, "/api/users/foo", nil))
+		require.False(t, found)
+
+		found, _ = ar.HasRoute(httptest.NewRequest(http.MethodDelete, "/files/a", nil))
+		require.False(t, found)
+	})
+
+	t.Run("groups are mounted and inherit middleware", func(t *testing.T) {
+		mux := chi.NewRouter()
+		ar := NewRouter(mux)
+
+		var calls []string
+		middleware := func(name string) MiddlewareFunc {
+			return func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					calls = append(calls, name)
+					next.ServeHTTP(w, req)
+				})
+			}
+		}
+
+		ar.Use(middleware("root"))
+		group := ar.Group("/api").Group("/v1/")
+		group.Use(middleware("group"))
+		group.AddRoute(http.MethodGet, "/items", okHandler, middleware("route"))
+		// Grouping twice on the same prefix reuses the mounted router
+		ar.Group("/api").AddRoute(http.MethodGet, "/other", okHandler)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/items", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, []string{"root", "group", "route"}, calls)
+
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/other", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+
+	t.Run("group with empty path prefix", func(t *testing.T) {
+		mux := chi.NewRouter()
+		ar := NewRouter(mux)
+
+		middlewareCalled := false
+		group := ar.Group("")
+		group.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				middlewareCalled = true
+				next.ServeHTTP(w, req)
+			})
+		})
+		group.AddRoute(http.MethodGet, "/test", okHandler)
+		ar.AddRoute(http.MethodGet, "/other", okHandler)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/other", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.False(t, middlewareCalled)
+
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.True(t, middlewareCalled)
+	})
+
+	t.Run("host routes", func(t *testing.T) {
+		mux := chi.NewRouter()
+		ar := NewRouter(mux)
+		ar.AddRoute(http.MethodGet, "/root", okHandler)
+		ar.Host("api.example.com").AddRoute(http.MethodGet, "/host", okHandler)
+		ar.Host("admin.example.com:8080").AddRoute(http.MethodGet, "/host", func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("admin"))
+		})
+
+		testCases := []struct {
+			host   string
+			path   string
+			status int
+			body   string
+		}{
+			{host: "api.example.com", path: "/host", status: http.StatusOK, body: "OK"},
+			{host: "api.example.com:8080", path: "/host", status: http.StatusOK, body: "OK"},
+			{host: "admin.example.com:8080", path: "/host", status: http.StatusOK, body: "admin"},
+			{host: "admin.example.com", path: "/host", status: http.StatusNotFound},
+			{host: "other.example.com", path: "/host", status: http.StatusNotFound},
+			{host: "other.example.com", path: "/root", status: http.StatusOK, body: "OK"},
+		}
+		for _, test := range testCases {
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			req.Host = test.host
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			require.Equal(t, test.status, w.Result().StatusCode, test.host+test.path)
+			if test.body != "" {
+				require.Equal(t, test.body, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("host routes keep the catch-all and the handlers of the router", func(t *testing.T) {
+		mux := chi.NewRouter()
+		mux.MethodNotAllowed(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			w.Write([]byte("custom method not allowed"))
+		})
+		ar := NewRouter(mux)
+		ar.AddRoute(http.MethodGet, "/*", func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("catch-all"))
+		})
+		ar.AddRoute(http.MethodPost, "/root", okHandler)
+		require.NotPanics(t, func() {
+			ar.Host("api.example.com").AddRoute(http.MethodGet, "/host", okHandler)
+		})
+
+		testCases := []struct {
+			host   string
+			method string
+			path   string
+			status int
+			body   string
+		}{
+			{host: "api.example.com", method: http.MethodGet, path: "/host", status: http.StatusOK, body: "OK"},
+			{host: "api.example.com", method: http.MethodGet, path: "/other", status: http.StatusOK, body: "catch-all"},
+			{host: "other.example.com", method: http.MethodGet, path: "/host", status: http.StatusOK, body: "catch-all"},
+			{host: "api.example.com", method: http.MethodPost, path: "/root", status: http.StatusOK, body: "OK"},
+			{host: "api.example.com", method: http.MethodPut, path: "/other", status: http.StatusMethodNotAllowed, body: "custom method not allowed"},
+		}
+		for _, test := range testCases {
+			req := httptest.NewRequest(test.method, test.path, nil)
+			req.Host = test.host
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			require.Equal(t, test.status, w.Result().StatusCode, test.host+test.path)
+			require.Equal(t, test.body, w.Body.String(), test.host+test.path)
+		}
+	})
+
+	t.Run("host routes of a router with routes added directly", func(t *testing.T) {
+		mux := chi.NewRouter()
+		mux.Get("/root", okHandler)
+		mux.NotFound(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("custom not found"))
+		})
+		ar := NewRouter(mux)
+		require.NotPanics(t, func() {
+			ar.Host("api.example.com").AddRoute(http.MethodGet, "/host", okHandler)
+		})
+
+		testCases := []struct {
+			host   string
+			path   string
+			status int
+			body   string
+		}{
+			{host: "api.example.com", path: "/host", status: http.StatusOK, body: "OK"},
+			{host: "api.example.com", path: "/root", status: http.StatusOK, body: "OK"},
+			{host: "other.example.com", path: "/host", status: http.StatusNotFound, body: "custom not found"},
+		}
+		for _, test := range testCases {
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			req.Host = test.host
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			require.Equal(t, test.status, w.Result().StatusCode, test.host+test.path)
+			require.Equal(t, test.body, w.Body.String(), test.host+test.path)
+		}
+	})
+
+	t.Run("validation middleware receives the url params", func(t *testing.T) {
+		mux := chi.NewRouter()
+		ar := NewRouter(mux)
+		validatingRouter := ar.(apirouter.ValidatingRouter[MiddlewareFunc])
+
+		var params map[string]string
+		ar.Group("/users").AddRoute(http.MethodGet, "/{id}/files/*", okHandler, validatingRouter.ValidationMiddleware(func(req *http.Request, pathParams map[string]string) error {
+			params = pathParams
+			if pathParams["id"] == "invalid" {
+				return errors.New("invalid user")
+			}
+			return nil
+		}))
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/files/a/b", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, map[string]string{"id": "1", "wildcard": "a/b"}, params)
+
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/invalid/files/a", nil))
+		require.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+		require.JSONEq(t, `{"message":"invalid user"}`, w.Body.String())
+	})
+
+	t.Run("create openapi handler", func(t *testing.T) {
+		handler := NewRouter(chi.NewRouter()).SwaggerHandler("text/html", []byte("some data"))
+
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "text/html", w.Result().Header.Get("Content-Type"))
+		require.Equal(t, "some data", w.Body.String())
+	})
+}
+
+func TestTransformPathToOasPath(t *testing.T) {
+	ar := NewRouter(chi.NewRouter())
+
+	testCases := map[string]string{
+		"/":                            "/",
+		"/users":                       "/users",
+		"/users/{id}":                  "/users/{id}",
+		"/users/{id:[0-9]+}":           "/users/{id}",
+		"/users/{id:[0-9]+}/":          "/users/{id}/",
+		"/codes/{code:[a-z]{3}}/items": "/codes/{code}/items",
+		"/{year:\\d{4}}-{month}":       "/{year}-{month}",
+		"/files/*":                     "/files/{wildcard}",
+		"/users/{id}/files/*":          "/users/{id}/files/{wildcard}",
+		"/*":                           "/{wildcard}",
+	}
+	for path, expected := range testCases {
+		require.Equal(t, expected, ar.TransformPathToOasPath(path), path)
+	}
+}
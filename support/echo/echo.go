@@ -11,6 +11,9 @@ import (
 type Route = *echo.Route
 
 var _ apirouter.Router[echo.HandlerFunc, echo.MiddlewareFunc, Route] = (*echoRouter)(nil)
+var _ apirouter.ValidatingRouter[echo.MiddlewareFunc] = (*echoRouter)(nil)
+var _ apirouter.ResponseValidatingRouter[echo.MiddlewareFunc] = (*echoRouter)(nil)
+var _ apirouter.BindingRouter[echo.HandlerFunc] = (*echoRouter)(nil)
 
 type echoRouter struct {
 	router *echo.Echo
@@ -128,3 +131,54 @@ func (r echoRouter) Use(middleware ...echo.MiddlewareFunc) {
 		r.router.Use(middleware...)
 	}
 }
+
+func (r echoRouter) ValidationMiddleware(validate apirouter.RequestValidator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := validate(c.Request(), pathParams(c)); err != nil {
+				status, body := apirouter.ErrorResponse(err)
+				return c.JSONBlob(status, body)
+			}
+			return next(c)
+		}
+	}
+}
+
+func (r echoRouter) ResponseValidationMiddleware(validate apirouter.ResponseValidator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			res := c.Response()
+			writer := res.Writer
+			buffer := apirouter.NewResponseBuffer(writer)
+			res.Writer = buffer
+
+			// Errors are handled here, so that the error response is validated too
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+			res.Writer = writer
+
+			if err := validate(c.Request(), pathParams(c), buffer.Status(), writer.Header(), buffer.Body()); err != nil {
+				buffer.SendError(err)
+				return nil
+			}
+			buffer.Send()
+			return nil
+		}
+	}
+}
+
+func (r echoRouter) BindingHandler(handler apirouter.BindingHandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		handler(c.Response(), c.Request(), pathParams(c))
+		return nil
+	}
+}
+
+func pathParams(c echo.Context) map[string]string {
+	params := make(map[string]string, len(c.ParamNames()))
+	for _, name := range c.ParamNames() {
+		params[name] = c.Param(name)
+	}
+	return params
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	oasEcho "go.lumeweb.com/gswagger/support/echo"
@@ -232,3 +233,174 @@ func readFile(t *testing.T, path string) string {
 
 	return string(fileContent)
 }
+
+func TestEchoRequestValidation(t *testing.T) {
+	e := echo.New()
+	router, err := swagger.NewRouter(oasEcho.NewRouter(e), swagger.Options[echo.HandlerFunc, echo.MiddlewareFunc, *echo.Route]{
+		Context: context.Background(),
+		Openapi: &openapi3.T{
+			Info: &openapi3.Info{
+				Title:   swaggerOpenapiTitle,
+				Version: swaggerOpenapiVersion,
+			},
+		},
+		ValidateRequests: true,
+	})
+	require.NoError(t, err)
+
+	_, err = router.AddRoute(http.MethodGet, "/users/:userId", okHandler, swagger.Definitions{
+		PathParams: swagger.ParameterValue{
+			"userId": {Schema: &swagger.Schema{Value: 0}},
+		},
+		Querystring: swagger.ParameterValue{
+			"limit": {Schema: &swagger.Schema{Value: 0}},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, router.GenerateAndExposeOpenapi())
+
+	t.Run("valid request", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42?limit=3", nil))
+
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "OK", readBody(t, w.Result().Body))
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/foo?limit=3", nil))
+
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+		require.Contains(t, readBody(t, w.Result().Body), `"in":"path","name":"userId"`)
+	})
+}
+
+func TestEchoResponseValidation(t *testing.T) {
+	e := echo.New()
+	router, err := swagger.NewRouter(oasEcho.NewRouter(e), swagger.Options[echo.HandlerFunc, echo.MiddlewareFunc, *echo.Route]{
+		Context: context.Background(),
+		Openapi: &openapi3.T{
+			Info: &openapi3.Info{
+				Title:   swaggerOpenapiTitle,
+				Version: swaggerOpenapiVersion,
+			},
+		},
+		ResponseValidation:             swagger.ResponseValidationFail,
+		ResponseValidationErrorHandler: func(req *http.Request, err error) {},
+	})
+	require.NoError(t, err)
+
+	type User struct {
+		Name string `json:"name" jsonschema:"required"`
+	}
+	_, err = router.AddRoute(http.MethodGet, "/users/:userId", func(c echo.Context) error {
+		switch c.Param("userId") {
+		case "invalid":
+			return c.JSON(http.StatusOK, map[string]int{"name": 1})
+		case "error":
+			return echo.ErrForbidden
+		}
+		return c.JSON(http.StatusOK, User{Name: "Jane"})
+	}, swagger.Definitions{
+		Responses: map[int]swagger.ContentValue{
+			200: {Content: swagger.Content{"application/json": {Value: User{}}}},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, router.GenerateAndExposeOpenapi())
+
+	t.Run("valid response", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.JSONEq(t, `{"name":"Jane"}`, readBody(t, w.Result().Body))
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/invalid", nil))
+
+		require.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+		require.Contains(t, readBody(t, w.Result().Body), `"message":"response validation failed"`)
+	})
+
+	t.Run("undocumented error returned by the handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/error", nil))
+
+		require.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+		require.Contains(t, readBody(t, w.Result().Body), `"status":403`)
+	})
+}
+
+func TestEchoTypedRoute(t *testing.T) {
+	e := echo.New()
+	router, err := swagger.NewRouter(oasEcho.NewRouter(e), swagger.Options[echo.HandlerFunc, echo.MiddlewareFunc, *echo.Route]{
+		Context: context.Background(),
+		Openapi: &openapi3.T{
+			Info: &openapi3.Info{
+				Title:   swaggerOpenapiTitle,
+				Version: swaggerOpenapiVersion,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	type User struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	type request struct {
+		UserID int `path:"userId"`
+		Body   User
+	}
+	_, err = swagger.AddTypedRoute(router, http.MethodPut, "/users/:userId", func(ctx context.Context, req request) (User, error) {
+		return User{ID: req.UserID, Name: req.Body.Name}, nil
+	}, swagger.Definitions{})
+	require.NoError(t, err)
+
+	t.Run("binds the request", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/users/42", strings.NewReader(`{"name":"Jane"}`)))
+
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.JSONEq(t, `{"id":42,"name":"Jane"}`, readBody(t, w.Result().Body))
+	})
+
+	t.Run("invalid path parameter", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/users/foo", strings.NewReader(`{"name":"Jane"}`)))
+
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+		require.Contains(t, readBody(t, w.Result().Body), `"name":"userId"`)
+	})
+}
+
+func TestEchoDocumentationUI(t *testing.T) {
+	e := echo.New()
+	router, err := swagger.NewRouter(oasEcho.NewRouter(e), swagger.Options[echo.HandlerFunc, echo.MiddlewareFunc, *echo.Route]{
+		Context: context.Background(),
+		Openapi: &openapi3.T{
+			Info: &openapi3.Info{
+				Title:   swaggerOpenapiTitle,
+				Version: swaggerOpenapiVersion,
+			},
+		},
+		PathPrefix:          "/api",
+		UIDocumentationPath: swagger.DefaultUIDocumentationPath,
+	})
+	require.NoError(t, err)
+	require.NoError(t, router.GenerateAndExposeOpenapi())
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/documentation", nil))
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.Contains(t, readBody(t, w.Result().Body), `data-spec-url="/api/documentation/json"`)
+
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/documentation/ui.js", nil))
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	require.Equal(t, "text/javascript; charset=utf-8", w.Result().Header.Get("Content-Type"))
+}
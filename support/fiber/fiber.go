@@ -2,6 +2,7 @@ package fiber
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"go.lumeweb.com/gswagger/apirouter"
 	"net/http"
 )
@@ -10,6 +11,9 @@ type HandlerFunc = fiber.Handler
 type Route = fiber.Router
 
 var _ apirouter.Router[HandlerFunc, HandlerFunc, Route] = (*fiberRouter)(nil)
+var _ apirouter.ValidatingRouter[HandlerFunc] = (*fiberRouter)(nil)
+var _ apirouter.ResponseValidatingRouter[HandlerFunc] = (*fiberRouter)(nil)
+var _ apirouter.BindingRouter[HandlerFunc] = (*fiberRouter)(nil)
 
 type fiberRouter struct {
 	router fiber.Router // Can be *fiber.App or fiber.Router (from Group)
@@ -73,6 +77,58 @@ func (r fiberRouter) TransformPathToOasPath(path string) string {
 	return apirouter.TransformPathParamsWithColon(path)
 }
 
+func (r fiberRouter) ValidationMiddleware(validate apirouter.RequestValidator) HandlerFunc {
+	return func(c *fiber.Ctx) error {
+		req, err := adaptor.ConvertRequest(c, true)
+		if err != nil {
+			return err
+		}
+		if err := validate(req, c.AllParams()); err != nil {
+			status, body := apirouter.ErrorResponse(err)
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return c.Status(status).Send(body)
+		}
+		return c.Next()
+	}
+}
+
+func (r fiberRouter) ResponseValidationMiddleware(validate apirouter.ResponseValidator) HandlerFunc {
+	return func(c *fiber.Ctx) error {
+		req, err := adaptor.ConvertRequest(c, true)
+		if err != nil {
+			return err
+		}
+		pathParams := c.AllParams()
+
+		// Errors are handled here, so that the error response is validated too
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				return err
+			}
+		}
+
+		header := make(http.Header)
+		c.Response().Header.VisitAll(func(key, value []byte) {
+			header.Add(string(key), string(value))
+		})
+		if err := validate(req, pathParams, c.Response().StatusCode(), header, c.Response().Body()); err != nil {
+			status, body := apirouter.ErrorResponse(err)
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return c.Status(status).Send(body)
+		}
+		return nil
+	}
+}
+
+func (r fiberRouter) BindingHandler(handler apirouter.BindingHandlerFunc) HandlerFunc {
+	return func(c *fiber.Ctx) error {
+		pathParams := c.AllParams()
+		return adaptor.HTTPHandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			handler(w, req, pathParams)
+		})(c)
+	}
+}
+
 func useMiddleware(router fiber.Router, middleware ...HandlerFunc) fiber.Router {
 	if len(middleware) > 0 {
 		for _, mw := range middleware {
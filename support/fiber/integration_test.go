@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	swagger "go.lumeweb.com/gswagger"
@@ -164,3 +165,187 @@ func readFile(t *testing.T, path string) string {
 
 	return string(fileContent)
 }
+
+func TestFiberRequestValidation(t *testing.T) {
+	fiberRouter := fiber.New()
+	router, err := swagger.NewRouter(oasFiber.NewRouter(fiberRouter), swagger.Options[oasFiber.HandlerFunc, fiber.Handler, oasFiber.Route]{
+		Context: context.Background(),
+		Openapi: &openapi3.T{
+			Info: &openapi3.Info{
+				Title:   swaggerOpenapiTitle,
+				Version: swaggerOpenapiVersion,
+			},
+		},
+		ValidateRequests: true,
+	})
+	require.NoError(t, err)
+
+	type User struct {
+		Name string `json:"name" jsonschema:"required"`
+	}
+	_, err = router.AddRoute(http.MethodPost, "/users/:userId", okHandler, swagger.Definitions{
+		PathParams: swagger.ParameterValue{
+			"userId": {Schema: &swagger.Schema{Value: 0}},
+		},
+		RequestBody: &swagger.ContentValue{
+			Content:  swagger.Content{"application/json": {Value: User{}}},
+			Required: true,
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, router.GenerateAndExposeOpenapi())
+
+	newRequest := func(target, body string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		return r
+	}
+
+	t.Run("valid request", func(t *testing.T) {
+		resp, err := fiberRouter.Test(newRequest("/users/42", `{"name":"Jane"}`))
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "OK", readBody(t, resp.Body))
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		resp, err := fiberRouter.Test(newRequest("/users/foo", `{}`))
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		body := readBody(t, resp.Body)
+		require.Contains(t, body, `"in":"path","name":"userId"`)
+		require.Contains(t, body, `"in":"body"`)
+	})
+}
+
+func TestFiberResponseValidation(t *testing.T) {
+	fiberRouter := fiber.New()
+	router, err := swagger.NewRouter(oasFiber.NewRouter(fiberRouter), swagger.Options[oasFiber.HandlerFunc, fiber.Handler, oasFiber.Route]{
+		Context: context.Background(),
+		Openapi: &openapi3.T{
+			Info: &openapi3.Info{
+				Title:   swaggerOpenapiTitle,
+				Version: swaggerOpenapiVersion,
+			},
+		},
+		ResponseValidation:             swagger.ResponseValidationFail,
+		ResponseValidationErrorHandler: func(req *http.Request, err error) {},
+	})
+	require.NoError(t, err)
+
+	type User struct {
+		Name string `json:"name" jsonschema:"required"`
+	}
+	_, err = router.AddRoute(http.MethodGet, "/users/:userId", func(c *fiber.Ctx) error {
+		switch c.Params("userId") {
+		case "invalid":
+			return c.JSON(map[string]int{"name": 1})
+		case "error":
+			return fiber.ErrForbidden
+		}
+		return c.JSON(User{Name: "Jane"})
+	}, swagger.Definitions{
+		Responses: map[int]swagger.ContentValue{
+			200: {Content: swagger.Content{"application/json": {Value: User{}}}},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, router.GenerateAndExposeOpenapi())
+
+	t.Run("valid response", func(t *testing.T) {
+		resp, err := fiberRouter.Test(httptest.NewRequest(http.MethodGet, "/users/1", nil))
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.JSONEq(t, `{"name":"Jane"}`, readBody(t, resp.Body))
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		resp, err := fiberRouter.Test(httptest.NewRequest(http.MethodGet, "/users/invalid", nil))
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		require.Contains(t, readBody(t, resp.Body), `"message":"response validation failed"`)
+	})
+
+	t.Run("undocumented error returned by the handler", func(t *testing.T) {
+		resp, err := fiberRouter.Test(httptest.NewRequest(http.MethodGet, "/users/error", nil))
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		require.Contains(t, readBody(t, resp.Body), `"status":403`)
+	})
+}
+
+func TestFiberTypedRoute(t *testing.T) {
+	fiberRouter := fiber.New()
+	router, err := swagger.NewRouter(oasFiber.NewRouter(fiberRouter), swagger.Options[oasFiber.HandlerFunc, fiber.Handler, oasFiber.Route]{
+		Context: context.Background(),
+		Openapi: &openapi3.T{
+			Info: &openapi3.Info{
+				Title:   swaggerOpenapiTitle,
+				Version: swaggerOpenapiVersion,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	type User struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	type request struct {
+		UserID int `path:"userId"`
+		Body   User
+	}
+	_, err = swagger.AddTypedRoute(router, http.MethodPut, "/users/:userId", func(ctx context.Context, req request) (User, error) {
+		return User{ID: req.UserID, Name: req.Body.Name}, nil
+	}, swagger.Definitions{})
+	require.NoError(t, err)
+
+	t.Run("binds the request", func(t *testing.T) {
+		resp, err := fiberRouter.Test(httptest.NewRequest(http.MethodPut, "/users/42", strings.NewReader(`{"name":"Jane"}`)))
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.JSONEq(t, `{"id":42,"name":"Jane"}`, readBody(t, resp.Body))
+	})
+
+	t.Run("invalid path parameter", func(t *testing.T) {
+		resp, err := fiberRouter.Test(httptest.NewRequest(http.MethodPut, "/users/foo", strings.NewReader(`{"name":"Jane"}`)))
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Contains(t, readBody(t, resp.Body), `"name":"userId"`)
+	})
+}
+
+func TestFiberDocumentationUI(t *testing.T) {
+	fiberRouter := fiber.New()
+	router, err := swagger.NewRouter(oasFiber.NewRouter(fiberRouter), swagger.Options[oasFiber.HandlerFunc, fiber.Handler, oasFiber.Route]{
+		Context: context.Background(),
+		Openapi: &openapi3.T{
+			Info: &openapi3.Info{
+				Title:   swaggerOpenapiTitle,
+				Version: swaggerOpenapiVersion,
+			},
+		},
+		PathPrefix:          "/api",
+		UIDocumentationPath: swagger.DefaultUIDocumentationPath,
+	})
+	require.NoError(t, err)
+	require.NoError(t, router.GenerateAndExposeOpenapi())
+
+	resp, err := fiberRouter.Test(httptest.NewRequest(http.MethodGet, "/api/documentation", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, readBody(t, resp.Body), `data-spec-url="/api/documentation/json"`)
+
+	resp, err = fiberRouter.Test(httptest.NewRequest(http.MethodGet, "/api/documentation/ui.js", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/javascript; charset=utf-8", resp.Header.Get("Content-Type"))
+}
@@ -0,0 +1,231 @@
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.lumeweb.com/gswagger/apirouter"
+)
+
+type HandlerFunc = gin.HandlerFunc
+type Route = gin.IRoutes
+
+var _ apirouter.Router[HandlerFunc, HandlerFunc, Route] = (*ginRouter)(nil)
+var _ apirouter.ValidatingRouter[HandlerFunc] = (*ginRouter)(nil)
+var _ apirouter.ResponseValidatingRouter[HandlerFunc] = (*ginRouter)(nil)
+var _ apirouter.BindingRouter[HandlerFunc] = (*ginRouter)(nil)
+
+// matchedPathKey is the context key of the gin path matched by HasRoute.
+const matchedPathKey = "gswagger.matchedPath"
+
+type ginRouter struct {
+	router *gin.Engine
+	group  *gin.RouterGroup
+	// matcher mirrors the routes, groups and host filters of the router,
+	// with handlers recording the matched path instead of serving requests
+	matcher      *gin.Engine
+	matcherGroup *gin.RouterGroup
+}
+
+func NewRouter(router *gin.Engine) apirouter.Router[HandlerFunc, HandlerFunc, Route] {
+	matcher := gin.New()
+	matcher.RedirectTrailingSlash = false
+	matcher.RedirectFixedPath = false
+	matcher.UseRawPath = router.UseRawPath
+	matcher.RemoveExtraSlash = router.RemoveExtraSlash
+	return ginRouter{
+		router:  router,
+		matcher: matcher,
+	}
+}
+
+func (r ginRouter) routes() gin.IRouter {
+	if r.group != nil {
+		return r.group
+	}
+	return r.router
+}
+
+func (r ginRouter) matcherRoutes() gin.IRouter {
+	if r.matcherGroup != nil {
+		return r.matcherGroup
+	}
+	return r.matcher
+}
+
+func (r ginRouter) AddRoute(method string, path string, handler HandlerFunc, middleware ...HandlerFunc) Route {
+	handlers := make([]HandlerFunc, 0, len(middleware)+1)
+	handlers = append(handlers, middleware...)
+	handlers = append(handlers, handler)
+	route := r.routes().Handle(method, path, handlers...)
+	r.matcherRoutes().Handle(method, path, func(c *gin.Context) {
+		c.Set(matchedPathKey, c.FullPath())
+	})
+	return route
+}
+
+func (r ginRouter) SwaggerHandler(contentType string, blob []byte) HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, contentType, blob)
+	}
+}
+
+// TransformPathToOasPath converts a gin path to an OpenAPI path:
+// both :param and *wildcard become {param} and {wildcard}.
+func (r ginRouter) TransformPathToOasPath(path string) string {
+	segments := strings.Split(apirouter.TransformPathParamsWithColon(path), "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + strings.TrimPrefix(segment, "*") + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func (r ginRouter) Router(group bool) any {
+	if r.group != nil && group {
+		return r.group
+	}
+	return r.router
+}
+
+func (r ginRouter) Group(pathPrefix string) apirouter.Router[HandlerFunc, HandlerFunc, Route] {
+	return ginRouter{
+		router:       r.router,
+		group:        r.routes().Group(pathPrefix),
+		matcher:      r.matcher,
+		matcherGroup: r.matcherRoutes().Group(pathPrefix),
+	}
+}
+
+func (r ginRouter) Host(host string) apirouter.Router[HandlerFunc, HandlerFunc, Route] {
+	// Gin doesn't natively support host-based routing, so we'll use a middleware
+	// to filter requests by host
+	return ginRouter{
+		router:       r.router,
+		group:        r.routes().Group("", hostFilter(host)),
+		matcher:      r.matcher,
+		matcherGroup: r.matcherRoutes().Group("", hostFilter(host)),
+	}
+}
+
+// hostFilter returns a middleware responding 404 to the requests for other hosts.
+func hostFilter(host string) HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Host != host {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Use adds middleware to the routes registered after it, as gin does.
+func (r ginRouter) Use(middleware ...HandlerFunc) {
+	r.routes().Use(middleware...)
+}
+
+// HasRoute reports whether a route added with the router matches req, with
+// the matched gin path. The request is routed by gin through a copy of the
+// routes and host filters, so that no handler is run. Routes added to the gin
+// engine directly are not known.
+func (r ginRouter) HasRoute(req *http.Request) (bool, string) {
+	c := gin.CreateTestContextOnly(httptest.NewRecorder(), r.matcher)
+	c.Request = req
+	r.matcher.HandleContext(c)
+
+	matched, ok := c.Get(matchedPathKey)
+	if !ok {
+		return false, ""
+	}
+	return true, matched.(string)
+}
+
+func (r ginRouter) ValidationMiddleware(validate apirouter.RequestValidator) HandlerFunc {
+	return func(c *gin.Context) {
+		if err := validate(c.Request, pathParams(c)); err != nil {
+			status, body := apirouter.ErrorResponse(err)
+			c.Data(status, "application/json", body)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func (r ginRouter) ResponseValidationMiddleware(validate apirouter.ResponseValidator) HandlerFunc {
+	return func(c *gin.Context) {
+		writer := c.Writer
+		buffer := &bufferedWriter{
+			ResponseWriter: writer,
+			buffer:         apirouter.NewResponseBuffer(writer),
+		}
+		c.Writer = buffer
+		c.Next()
+		c.Writer = writer
+
+		if err := validate(c.Request, pathParams(c), buffer.Status(), writer.Header(), buffer.buffer.Body()); err != nil {
+			buffer.buffer.SendError(err)
+			return
+		}
+		buffer.buffer.Send()
+	}
+}
+
+func (r ginRouter) BindingHandler(handler apirouter.BindingHandlerFunc) HandlerFunc {
+	return func(c *gin.Context) {
+		handler(c.Writer, c.Request, pathParams(c))
+	}
+}
+
+// bufferedWriter is a gin.ResponseWriter keeping the response in memory,
+// so that it can be validated before it is sent.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	buffer  *apirouter.ResponseBuffer
+	written bool
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	w.written = true
+	w.buffer.WriteHeader(status)
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.buffer.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.buffer.Status()
+}
+
+func (w *bufferedWriter) Size() int {
+	return len(w.buffer.Body())
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+// pathParams returns the params matched by gin. Wildcard values are returned
+// without the leading /, as documented by the OpenAPI path.
+func pathParams(c *gin.Context) map[string]string {
+	params := make(map[string]string, len(c.Params))
+	for _, param := range c.Params {
+		value := param.Value
+		if strings.Contains(c.FullPath(), "*"+param.Key) {
+			value = strings.TrimPrefix(value, "/")
+		}
+		params[param.Key] = value
+	}
+	return params
+}
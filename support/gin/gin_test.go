@@ -0,0 +1,214 @@
+package gin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.lumeweb.com/gswagger/apirouter"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestGinRouter(t *testing.T) {
+	okHandler := func(c *gin.Context) {
+		c.String(http.StatusOK, "OK")
+	}
+
+	t.Run("add route", func(t *testing.T) {
+		engine := gin.New()
+		ar := NewRouter(engine)
+
+		ar.AddRoute(http.MethodGet, "/users/:id", func(c *gin.Context) {
+			c.String(http.StatusOK, c.Param("id"))
+		})
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "42", w.Body.String())
+	})
+
+	t.Run("HasRoute uses the gin routes", func(t *testing.T) {
+		ar := NewRouter(gin.New())
+		ar.AddRoute(http.MethodGet, "/files/*path", okHandler)
+		ar.AddRoute(http.MethodGet, "/users/:id", okHandler)
+		ar.Group("/users").AddRoute(http.MethodGet, "/me", okHandler)
+
+		testCases := []struct {
+			method  string
+			path    string
+			found   bool
+			pattern string
+		}{
+			{method: http.MethodGet, path: "/files/a/b.txt", found: true, pattern: "/files/*path"},
+			{method: http.MethodGet, path: "/users/1", found: true, pattern: "/users/:id"},
+			{method: http.MethodGet, path: "/users/me", found: true, pattern: "/users/me"},
+			{method: http.MethodGet, path: "/users/1/other", found: false},
+			{method: http.MethodGet, path: "/users/", found: false},
+			{method: http.MethodDelete, path: "/users/1", found: false},
+			{method: http.MethodGet, path: "/not-found", found: false},
+		}
+		for _, test := range testCases {
+			found, pattern := ar.HasRoute(httptest.NewRequest(test.method, test.path, nil))
+			require.Equal(t, test.found, found, test.path)
+			require.Equal(t, test.pattern, pattern, test.path)
+		}
+	})
+
+	t.Run("HasRoute respects the host filter", func(t *testing.T) {
+		ar := NewRouter(gin.New())
+		ar.Host("api.example.com").AddRoute(http.MethodGet, "/items/:id", okHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+		req.Host = "api.example.com"
+		found, pattern := ar.HasRoute(req)
+		require.True(t, found)
+		require.Equal(t, "/items/:id", pattern)
+
+		req = httptest.NewRequest(http.MethodGet, "/items/1", nil)
+		req.Host = "other.example.com"
+		found, pattern = ar.HasRoute(req)
+		require.False(t, found)
+		require.Empty(t, pattern)
+	})
+
+	t.Run("HasRoute does not run the handlers", func(t *testing.T) {
+		ar := NewRouter(gin.New())
+		called := false
+		ar.AddRoute(http.MethodPost, "/items", func(c *gin.Context) {
+			called = true
+		})
+
+		found, _ := ar.HasRoute(httptest.NewRequest(http.MethodPost, "/items", nil))
+		require.True(t, found)
+		require.False(t, called)
+	})
+
+	t.Run("groups prefix paths and inherit middleware", func(t *testing.T) {
+		engine := gin.New()
+		ar := NewRouter(engine)
+
+		var calls []string
+		middleware := func(name string) HandlerFunc {
+			return func(c *gin.Context) {
+				calls = append(calls, name)
+				c.Next()
+			}
+		}
+
+		ar.Use(middleware("root"))
+		group := ar.Group("/api").Group("/v1")
+		group.Use(middleware("group"))
+		group.AddRoute(http.MethodGet, "/items", okHandler, middleware("route"))
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/items", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, []string{"root", "group", "route"}, calls)
+	})
+
+	t.Run("host routes", func(t *testing.T) {
+		engine := gin.New()
+		ar := NewRouter(engine)
+		ar.Host("api.example.com").AddRoute(http.MethodGet, "/host", okHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/host", nil)
+		req.Host = "api.example.com"
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+		req = httptest.NewRequest(http.MethodGet, "/host", nil)
+		req.Host = "other.example.com"
+		w = httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("validation middleware receives the path params", func(t *testing.T) {
+		engine := gin.New()
+		ar := NewRouter(engine)
+		validatingRouter := ar.(apirouter.ValidatingRouter[HandlerFunc])
+
+		var params map[string]string
+		ar.AddRoute(http.MethodGet, "/users/:id/files/*path", okHandler, validatingRouter.ValidationMiddleware(func(req *http.Request, pathParams map[string]string) error {
+			params = pathParams
+			if pathParams["id"] == "invalid" {
+				return errors.New("invalid user")
+			}
+			return nil
+		}))
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/files/a/b", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, map[string]string{"id": "1", "path": "a/b"}, params)
+
+		w = httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/invalid/files/a", nil))
+		require.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+		require.JSONEq(t, `{"message":"invalid user"}`, w.Body.String())
+	})
+
+	t.Run("response validation middleware buffers the response", func(t *testing.T) {
+		engine := gin.New()
+		ar := NewRouter(engine)
+		validatingRouter := ar.(apirouter.ResponseValidatingRouter[HandlerFunc])
+
+		ar.AddRoute(http.MethodGet, "/items/:id", func(c *gin.Context) {
+			c.JSON(http.StatusCreated, gin.H{"id": c.Param("id")})
+		}, validatingRouter.ResponseValidationMiddleware(func(req *http.Request, pathParams map[string]string, status int, header http.Header, body []byte) error {
+			require.Equal(t, http.StatusCreated, status)
+			require.Contains(t, header.Get("Content-Type"), "application/json")
+			if pathParams["id"] == "invalid" {
+				return errors.New("invalid response")
+			}
+			return nil
+		}))
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+		require.Equal(t, http.StatusCreated, w.Result().StatusCode)
+		require.JSONEq(t, `{"id":"1"}`, w.Body.String())
+
+		w = httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/invalid", nil))
+		require.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+		require.JSONEq(t, `{"message":"invalid response"}`, w.Body.String())
+	})
+
+	t.Run("create openapi handler", func(t *testing.T) {
+		engine := gin.New()
+		ar := NewRouter(engine)
+		ar.AddRoute(http.MethodGet, "/doc", ar.SwaggerHandler("text/html", []byte("some data")))
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/doc", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "text/html", w.Result().Header.Get("Content-Type"))
+		require.Equal(t, "some data", w.Body.String())
+	})
+}
+
+func TestTransformPathToOasPath(t *testing.T) {
+	ar := NewRouter(gin.New())
+
+	testCases := map[string]string{
+		"/":                        "/",
+		"/users":                   "/users",
+		"/users/:id":               "/users/{id}",
+		"/users/:id/":              "/users/{id}/",
+		"/files/*path":             "/files/{path}",
+		"/users/:id/files/*path":   "/users/{id}/files/{path}",
+		"/:par1/:par2/items/:par3": "/{par1}/{par2}/items/{par3}",
+	}
+	for path, expected := range testCases {
+		require.Equal(t, expected, ar.TransformPathToOasPath(path), path)
+	}
+}
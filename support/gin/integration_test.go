@@ -0,0 +1,150 @@
+package gin_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	gogin "github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	swagger "go.lumeweb.com/gswagger"
+	"go.lumeweb.com/gswagger/support/gin"
+	"go.lumeweb.com/gswagger/support/testutils"
+)
+
+const (
+	swaggerOpenapiTitle   = "test openapi title"
+	swaggerOpenapiVersion = "test openapi version"
+)
+
+type SwaggerRouter = swagger.Router[gin.HandlerFunc, gin.HandlerFunc, gin.Route]
+
+func init() {
+	gogin.SetMode(gogin.TestMode)
+}
+
+func TestGinIntegration(t *testing.T) {
+	t.Run("router works correctly", func(t *testing.T) {
+		engine, oasRouter := setupSwagger(t)
+
+		err := oasRouter.GenerateAndExposeOpenapi()
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/hello", nil)
+
+		engine.ServeHTTP(w, r)
+
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "OK", readBody(t, w.Result().Body))
+
+		t.Run("and generate swagger", func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, swagger.DefaultJSONDocumentationPath, nil)
+
+			engine.ServeHTTP(w, r)
+
+			require.Equal(t, http.StatusOK, w.Result().StatusCode)
+			require.Equal(t, "application/json", w.Result().Header.Get("Content-Type"))
+
+			body := readBody(t, w.Result().Body)
+			testutils.AssertJSONMatchesFile(t, []byte(body), "../testdata/integration.json")
+		})
+	})
+
+	t.Run("wildcards are documented as path params", func(t *testing.T) {
+		engine, oasRouter := setupSwagger(t)
+
+		_, err := oasRouter.AddRoute(http.MethodGet, "/files/*path", func(c *gogin.Context) {
+			c.String(http.StatusOK, c.Param("path"))
+		}, swagger.Definitions{})
+		require.NoError(t, err)
+		require.NoError(t, oasRouter.GenerateAndExposeOpenapi())
+
+		files := oasRouter.GetSwaggerSchema().Paths.Value("/files/{path}")
+		require.NotNil(t, files)
+		require.Equal(t, "path", files.Get.Parameters[0].Value.Name)
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/a/b.txt", nil))
+		require.Equal(t, "/a/b.txt", readBody(t, w.Result().Body))
+	})
+
+	t.Run("groups and request validation", func(t *testing.T) {
+		engine := gogin.New()
+		router, err := swagger.NewRouter(gin.NewRouter(engine), swagger.Options[gin.HandlerFunc, gin.HandlerFunc, gin.Route]{
+			Context: context.Background(),
+			Openapi: &openapi3.T{
+				Info: &openapi3.Info{
+					Title:   swaggerOpenapiTitle,
+					Version: swaggerOpenapiVersion,
+				},
+			},
+			ValidateRequests: true,
+		})
+		require.NoError(t, err)
+
+		group, err := router.Group("/api")
+		require.NoError(t, err)
+		_, err = group.AddRoute(http.MethodGet, "/users/:id", okHandler, swagger.Definitions{
+			PathParams: swagger.ParameterValue{
+				"id": {Schema: &swagger.Schema{Value: 0}},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+		require.NotNil(t, router.GetSwaggerSchema().Paths.Value("/api/users/{id}"))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/users/1", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/users/foo", nil))
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, swagger.DefaultJSONDocumentationPath, nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+}
+
+func readBody(t *testing.T, requestBody io.ReadCloser) string {
+	t.Helper()
+
+	body, err := io.ReadAll(requestBody)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func setupSwagger(t *testing.T) (*gogin.Engine, *SwaggerRouter) {
+	t.Helper()
+
+	engine := gogin.New()
+	router, err := swagger.NewRouter(gin.NewRouter(engine), swagger.Options[gin.HandlerFunc, gin.HandlerFunc, gin.Route]{
+		Context: context.Background(),
+		Openapi: &openapi3.T{
+			Info: &openapi3.Info{
+				Title:   swaggerOpenapiTitle,
+				Version: swaggerOpenapiVersion,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = router.AddRawRoute(http.MethodGet, "/hello", okHandler, swagger.Operation{})
+	require.NoError(t, err)
+
+	_, err = router.AddRoute(http.MethodPost, "/hello/:value", okHandler, swagger.Definitions{})
+	require.NoError(t, err)
+
+	return engine, router
+}
+
+func okHandler(c *gogin.Context) {
+	c.String(http.StatusOK, "OK")
+}
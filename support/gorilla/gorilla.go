@@ -11,6 +11,9 @@ type HandlerFunc func(w http.ResponseWriter, req *http.Request)
 type Route = *mux.Route
 
 var _ apirouter.Router[HandlerFunc, mux.MiddlewareFunc, Route] = (*gorillaRouter)(nil)
+var _ apirouter.ValidatingRouter[mux.MiddlewareFunc] = (*gorillaRouter)(nil)
+var _ apirouter.ResponseValidatingRouter[mux.MiddlewareFunc] = (*gorillaRouter)(nil)
+var _ apirouter.BindingRouter[HandlerFunc] = (*gorillaRouter)(nil)
 
 func NewRouter(router *mux.Router) apirouter.Router[HandlerFunc, mux.MiddlewareFunc, Route] {
 	return gorillaRouter{
@@ -69,3 +72,39 @@ func (r gorillaRouter) Host(host string) apirouter.Router[HandlerFunc, mux.Middl
 		router: hostRouter,
 	}
 }
+
+func (r gorillaRouter) ValidationMiddleware(validate apirouter.RequestValidator) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if err := validate(req, mux.Vars(req)); err != nil {
+				status, body := apirouter.ErrorResponse(err)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				w.Write(body)
+				return
+			}
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+func (r gorillaRouter) ResponseValidationMiddleware(validate apirouter.ResponseValidator) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			buffer := apirouter.NewResponseBuffer(w)
+			next.ServeHTTP(buffer, req)
+
+			if err := validate(req, mux.Vars(req), buffer.Status(), w.Header(), buffer.Body()); err != nil {
+				buffer.SendError(err)
+				return
+			}
+			buffer.Send()
+		})
+	}
+}
+
+func (r gorillaRouter) BindingHandler(handler apirouter.BindingHandlerFunc) HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		handler(w, req, mux.Vars(req))
+	}
+}
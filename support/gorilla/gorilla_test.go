@@ -1,6 +1,7 @@
 package gorilla
 
 import (
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -165,6 +166,37 @@ func TestGorillaMuxRouter(t *testing.T) {
 		})
 	})
 
+	t.Run("validation middleware", func(t *testing.T) {
+		testRouter := mux.NewRouter()
+		testAR := NewRouter(testRouter)
+
+		var receivedParams map[string]string
+		validator := func(req *http.Request, pathParams map[string]string) error {
+			receivedParams = pathParams
+			if pathParams["id"] == "invalid" {
+				return errors.New("invalid id")
+			}
+			return nil
+		}
+		testAR.AddRoute(http.MethodGet, "/items/{id}", func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}, testAR.(apirouter.ValidatingRouter[mux.MiddlewareFunc]).ValidationMiddleware(validator))
+
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, map[string]string{"id": "1"}, receivedParams)
+
+		w = httptest.NewRecorder()
+		testRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/invalid", nil))
+		require.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+		require.Equal(t, "application/json", w.Result().Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(w.Result().Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"message":"invalid id"}`, string(body))
+	})
+
 	t.Run("create openapi handler", func(t *testing.T) {
 		handlerFunc := ar.SwaggerHandler("text/html", []byte("some data"))
 		muxRouter.HandleFunc("/oas", handlerFunc).Methods(http.MethodGet)
@@ -0,0 +1,196 @@
+package nethttp
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"go.lumeweb.com/gswagger/apirouter"
+)
+
+// HandlerFunc is the http type handler used by http.ServeMux
+type HandlerFunc func(w http.ResponseWriter, req *http.Request)
+
+// MiddlewareFunc wraps the handler of a route
+type MiddlewareFunc func(next http.Handler) http.Handler
+
+// Route is the pattern registered on the http.ServeMux
+type Route struct {
+	Method  string
+	Pattern string
+}
+
+var _ apirouter.Router[HandlerFunc, MiddlewareFunc, Route] = (*netHTTPRouter)(nil)
+var _ apirouter.ValidatingRouter[MiddlewareFunc] = (*netHTTPRouter)(nil)
+var _ apirouter.ResponseValidatingRouter[MiddlewareFunc] = (*netHTTPRouter)(nil)
+var _ apirouter.BindingRouter[HandlerFunc] = (*netHTTPRouter)(nil)
+
+// middlewareChain holds the middleware added with Use to a router.
+// Routes resolve the chain when serving requests, so that middleware added
+// after a route is registered applies to it too, as with gorilla/mux.
+type middlewareChain struct {
+	parent     *middlewareChain
+	middleware []MiddlewareFunc
+}
+
+func (c *middlewareChain) then(handler http.Handler) http.Handler {
+	for chain := c; chain != nil; chain = chain.parent {
+		for i := len(chain.middleware) - 1; i >= 0; i-- {
+			handler = chain.middleware[i](handler)
+		}
+	}
+	return handler
+}
+
+type netHTTPRouter struct {
+	router     *http.ServeMux
+	pathPrefix string
+	host       string
+	chain      *middlewareChain
+}
+
+// NewRouter returns an apirouter.Router registering routes on a Go 1.22+
+// http.ServeMux, with method-qualified patterns.
+func NewRouter(router *http.ServeMux) apirouter.Router[HandlerFunc, MiddlewareFunc, Route] {
+	return netHTTPRouter{
+		router: router,
+		chain:  &middlewareChain{},
+	}
+}
+
+func (r netHTTPRouter) AddRoute(method string, path string, handler HandlerFunc, middleware ...MiddlewareFunc) Route {
+	pattern := r.host + r.pathPrefix + stripMethodAndHost(path)
+	if method != "" {
+		pattern = method + " " + pattern
+	}
+
+	var routeHandler http.Handler = http.HandlerFunc(handler)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		routeHandler = middleware[i](routeHandler)
+	}
+	chain := r.chain
+	r.router.Handle(pattern, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		chain.then(routeHandler).ServeHTTP(w, req)
+	}))
+
+	return Route{Method: method, Pattern: pattern}
+}
+
+func (r netHTTPRouter) SwaggerHandler(contentType string, blob []byte) HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		w.Write(blob)
+	}
+}
+
+// TransformPathToOasPath converts a ServeMux pattern to an OpenAPI path:
+// the method and the host are removed, {name...} becomes {name} and {$} is dropped.
+func (r netHTTPRouter) TransformPathToOasPath(path string) string {
+	segments := strings.Split(stripMethodAndHost(path), "/")
+	for i, segment := range segments {
+		switch {
+		case segment == "{$}":
+			segments[i] = ""
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "...}"):
+			segments[i] = strings.TrimSuffix(segment, "...}") + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func (r netHTTPRouter) Router(_ bool) any {
+	return r.router
+}
+
+func (r netHTTPRouter) Group(pathPrefix string) apirouter.Router[HandlerFunc, MiddlewareFunc, Route] {
+	return netHTTPRouter{
+		router:     r.router,
+		pathPrefix: r.pathPrefix + strings.TrimSuffix(pathPrefix, "/"),
+		host:       r.host,
+		chain:      &middlewareChain{parent: r.chain},
+	}
+}
+
+func (r netHTTPRouter) Host(host string) apirouter.Router[HandlerFunc, MiddlewareFunc, Route] {
+	// ServeMux matches the host without the port
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	return netHTTPRouter{
+		router:     r.router,
+		pathPrefix: r.pathPrefix,
+		host:       host,
+		chain:      &middlewareChain{parent: r.chain},
+	}
+}
+
+func (r netHTTPRouter) Use(middleware ...MiddlewareFunc) {
+	r.chain.middleware = append(r.chain.middleware, middleware...)
+}
+
+func (r netHTTPRouter) HasRoute(req *http.Request) (bool, string) {
+	_, pattern := r.router.Handler(req)
+	return pattern != "", pattern
+}
+
+func (r netHTTPRouter) ValidationMiddleware(validate apirouter.RequestValidator) MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if err := validate(req, pathParams(req)); err != nil {
+				status, body := apirouter.ErrorResponse(err)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				w.Write(body)
+				return
+			}
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+func (r netHTTPRouter) ResponseValidationMiddleware(validate apirouter.ResponseValidator) MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			buffer := apirouter.NewResponseBuffer(w)
+			next.ServeHTTP(buffer, req)
+
+			if err := validate(req, pathParams(req), buffer.Status(), w.Header(), buffer.Body()); err != nil {
+				buffer.SendError(err)
+				return
+			}
+			buffer.Send()
+		})
+	}
+}
+
+func (r netHTTPRouter) BindingHandler(handler apirouter.BindingHandlerFunc) HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		handler(w, req, pathParams(req))
+	}
+}
+
+// pathParams returns the values of the wildcards of the pattern matched by req.
+func pathParams(req *http.Request) map[string]string {
+	params := map[string]string{}
+	for _, segment := range strings.Split(stripMethodAndHost(req.Pattern), "/") {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") || segment == "{$}" {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimSuffix(segment[1:], "}"), "...")
+		params[name] = req.PathValue(name)
+	}
+	return params
+}
+
+// stripMethodAndHost returns the path of a ServeMux pattern,
+// without the method and the host.
+func stripMethodAndHost(pattern string) string {
+	if i := strings.IndexAny(pattern, " \t"); i >= 0 {
+		pattern = strings.TrimLeft(pattern[i:], " \t")
+	}
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:]
+	}
+	return pattern
+}
@@ -0,0 +1,150 @@
+package nethttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.lumeweb.com/gswagger/apirouter"
+)
+
+func TestNetHTTPRouter(t *testing.T) {
+	okHandler := func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}
+
+	t.Run("add method-qualified route", func(t *testing.T) {
+		mux := http.NewServeMux()
+		ar := NewRouter(mux)
+
+		route := ar.AddRoute(http.MethodGet, "/users/{id}", func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(req.PathValue("id")))
+		})
+		require.Equal(t, Route{Method: http.MethodGet, Pattern: "GET /users/{id}"}, route)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "42", w.Body.String())
+
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/42", nil))
+		require.Equal(t, http.StatusMethodNotAllowed, w.Result().StatusCode)
+	})
+
+	t.Run("HasRoute is backed by ServeMux.Handler", func(t *testing.T) {
+		ar := NewRouter(http.NewServeMux())
+		ar.AddRoute(http.MethodGet, "/files/{path...}", okHandler)
+
+		found, pattern := ar.HasRoute(httptest.NewRequest(http.MethodGet, "/files/a/b.txt", nil))
+		require.True(t, found)
+		require.Equal(t, "GET /files/{path...}", pattern)
+
+		found, _ = ar.HasRoute(httptest.NewRequest(http.MethodGet, "/other", nil))
+		require.False(t, found)
+
+		found, _ = ar.HasRoute(httptest.NewRequest(http.MethodDelete, "/files/a", nil))
+		require.False(t, found)
+	})
+
+	t.Run("groups prefix paths and inherit middleware", func(t *testing.T) {
+		mux := http.NewServeMux()
+		ar := NewRouter(mux)
+
+		var calls []string
+		middleware := func(name string) MiddlewareFunc {
+			return func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					calls = append(calls, name)
+					next.ServeHTTP(w, req)
+				})
+			}
+		}
+
+		group := ar.Group("/api").Group("/v1/")
+		group.AddRoute(http.MethodGet, "/items", okHandler, middleware("route"))
+		// Middleware added after the route applies to it too
+		ar.Use(middleware("root"))
+		group.Use(middleware("group"))
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/items", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, []string{"root", "group", "route"}, calls)
+	})
+
+	t.Run("host routes ignore the port", func(t *testing.T) {
+		mux := http.NewServeMux()
+		ar := NewRouter(mux)
+		ar.Host("api.example.com:8080").AddRoute(http.MethodGet, "/host", okHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/host", nil)
+		req.Host = "api.example.com:8080"
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+		req = httptest.NewRequest(http.MethodGet, "/host", nil)
+		req.Host = "other.example.com"
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("validation middleware receives the path values", func(t *testing.T) {
+		mux := http.NewServeMux()
+		ar := NewRouter(mux)
+		validatingRouter := ar.(apirouter.ValidatingRouter[MiddlewareFunc])
+
+		var params map[string]string
+		ar.AddRoute(http.MethodGet, "/users/{id}/files/{path...}", okHandler, validatingRouter.ValidationMiddleware(func(req *http.Request, pathParams map[string]string) error {
+			params = pathParams
+			if pathParams["id"] == "invalid" {
+				return errors.New("invalid user")
+			}
+			return nil
+		}))
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1/files/a/b", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, map[string]string{"id": "1", "path": "a/b"}, params)
+
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/invalid/files/a", nil))
+		require.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+		require.JSONEq(t, `{"message":"invalid user"}`, w.Body.String())
+	})
+
+	t.Run("create openapi handler", func(t *testing.T) {
+		handler := NewRouter(http.NewServeMux()).SwaggerHandler("text/html", []byte("some data"))
+
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		require.Equal(t, "text/html", w.Result().Header.Get("Content-Type"))
+		require.Equal(t, "some data", w.Body.String())
+	})
+}
+
+func TestTransformPathToOasPath(t *testing.T) {
+	ar := NewRouter(http.NewServeMux())
+
+	testCases := map[string]string{
+		"/users":                       "/users",
+		"/users/{id}":                  "/users/{id}",
+		"/files/{path...}":             "/files/{path}",
+		"/{$}":                         "/",
+		"/users/{id}/{$}":              "/users/{id}/",
+		"GET /users/{id}":              "/users/{id}",
+		"GET api.example.com/users":    "/users",
+		"POST  /items/{rest...}":       "/items/{rest}",
+		"api.example.com/files/{p...}": "/files/{p}",
+	}
+	for path, expected := range testCases {
+		require.Equal(t, expected, ar.TransformPathToOasPath(path), path)
+	}
+}
@@ -0,0 +1,457 @@
+package swagger
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"go.lumeweb.com/gswagger/apirouter"
+)
+
+// ErrTypedRouteNotSupported is returned by AddTypedRoute when the framework
+// router does not implement apirouter.BindingRouter.
+var ErrTypedRouteNotSupported = errors.New("router does not support typed routes")
+
+// ErrTypedRoute indicates a request or response type which can not be used by AddTypedRoute.
+var ErrTypedRoute = errors.New("invalid typed route")
+
+// Struct tags used to bind request fields and to document parameters
+const (
+	pathTag   = "path"   // Field bound from a path parameter
+	queryTag  = "query"  // Field bound from a query parameter
+	headerTag = "header" // Field documenting a header parameter
+	cookieTag = "cookie" // Field documenting a cookie parameter
+)
+
+// bodyFieldName is the name of the request field the body is decoded into.
+const bodyFieldName = "Body"
+
+// TypedHandlerFunc handles a request decoded into Req, and returns the
+// response to encode. Errors implementing apirouter.HTTPError are written with
+// their status code, any other error as a generic 500 and passed to the
+// ErrorHandler option.
+type TypedHandlerFunc[Req any, Resp any] func(ctx context.Context, req Req) (Resp, error)
+
+// AddTypedRoute adds a route whose request and response are described by Go types.
+// The route documentation is derived from the types and merged with schema,
+// where definitions set explicitly take precedence.
+//
+// Req fields tagged `path:"name"` and `query:"name"` are bound from the path
+// and query parameters. The JSON request body is decoded into the Req field
+// named Body; when Req has neither tagged fields nor a Body field, the whole
+// Req is decoded from the body, or for GET, HEAD and DELETE requests each of
+// its fields is bound from the query parameter named after its json name.
+// An empty struct means no request body.
+//
+// Resp is encoded as JSON with status 200, or with the lowest 2xx status
+// documented in schema.Responses. Resp is not written, nor documented, when
+// the status is 204 or 304.
+//
+// The framework router must implement apirouter.BindingRouter.
+func AddTypedRoute[Req any, Resp any, HandlerFunc any, MiddlewareFunc any, Route any](r *Router[HandlerFunc, MiddlewareFunc, Route], method string, path string, handler TypedHandlerFunc[Req, Resp], schema Definitions, middleware ...MiddlewareFunc) (Route, error) {
+	bindingRouter, ok := r.router.(apirouter.BindingRouter[HandlerFunc])
+	if !ok {
+		return getZero[Route](), ErrTypedRouteNotSupported
+	}
+
+	binding, err := newRequestBinding(reflect.TypeFor[Req](), method)
+	if err != nil {
+		return getZero[Route](), fmt.Errorf("%w: %s", ErrTypedRoute, err)
+	}
+	schema = binding.definitions(schema)
+
+	successStatus := typedSuccessStatus(schema.Responses)
+	responses := make(map[int]ContentValue, len(schema.Responses)+1)
+	for code, response := range schema.Responses {
+		responses[code] = response
+	}
+	successResponse := responses[successStatus]
+	hasResponseBody := statusHasBody(successStatus)
+	if successResponse.Content == nil && hasResponseBody {
+		successResponse.Content = Content{jsonType: {Value: getZero[Resp]()}}
+	}
+	responses[successStatus] = successResponse
+	schema.Responses = responses
+
+	onError := r.options.errorHandler
+	if onError == nil {
+		onError = logError
+	}
+	writeError := func(w http.ResponseWriter, req *http.Request, err error) {
+		if !writeTypedError(w, err) {
+			onError(req, err)
+		}
+	}
+
+	typedHandler := bindingRouter.BindingHandler(func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		var input Req
+		if err := binding.bind(reflect.ValueOf(&input).Elem(), req, pathParams); err != nil {
+			writeError(w, req, err)
+			return
+		}
+
+		output, err := handler(req.Context(), input)
+		if err != nil {
+			writeError(w, req, err)
+			return
+		}
+		if !hasResponseBody {
+			w.WriteHeader(successStatus)
+			return
+		}
+
+		body, err := json.Marshal(output)
+		if err != nil {
+			writeError(w, req, fmt.Errorf("failed to encode response: %w", err))
+			return
+		}
+		w.Header().Set("Content-Type", jsonType)
+		w.WriteHeader(successStatus)
+		w.Write(body)
+	})
+
+	operation, err := r.newOperation(method, path, schema)
+	if err != nil {
+		return getZero[Route](), err
+	}
+	return r.addRawRoute(method, path, typedHandler, handlerName(handler), operation, middleware...)
+}
+
+// typedSuccessStatus returns the lowest 2xx status in responses, or 200.
+func typedSuccessStatus(responses map[int]ContentValue) int {
+	successStatus := 0
+	for code := range responses {
+		if code >= 200 && code < 300 && (successStatus == 0 || code < successStatus) {
+			successStatus = code
+		}
+	}
+	if successStatus == 0 {
+		return http.StatusOK
+	}
+	return successStatus
+}
+
+// statusHasBody reports whether a response with status can have a body.
+func statusHasBody(status int) bool {
+	return status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+// logError is the default handler of the errors of typed routes.
+func logError(_ *http.Request, err error) {
+	log.Printf("gswagger: %s", err)
+}
+
+// internalErrorBody is written in place of the errors of typed routes which
+// do not implement apirouter.HTTPError.
+var internalErrorBody, _ = json.Marshal(map[string]string{"message": http.StatusText(http.StatusInternalServerError)})
+
+// writeTypedError writes err as response, and reports whether it was sent to
+// the client. Errors not implementing apirouter.HTTPError may expose internal
+// details: a generic 500 is written in their place.
+func writeTypedError(w http.ResponseWriter, err error) bool {
+	var httpErr apirouter.HTTPError
+	sent := errors.As(err, &httpErr)
+	status, body := http.StatusInternalServerError, internalErrorBody
+	if sent {
+		status, body = apirouter.ErrorResponse(err)
+	}
+	w.Header().Set("Content-Type", jsonType)
+	w.WriteHeader(status)
+	w.Write(body)
+	return sent
+}
+
+// boundParameter is a request field bound from a parameter.
+type boundParameter struct {
+	in    string
+	name  string
+	field reflect.StructField
+}
+
+// requestBinding describes how a request is decoded into a Go type.
+type requestBinding struct {
+	typ        reflect.Type
+	parameters []boundParameter
+	// body is the index of the Body field, or nil if the whole type is the body
+	body    []int
+	hasBody bool
+}
+
+// newRequestBinding returns the binding of the requests of method into t.
+func newRequestBinding(t reflect.Type, method string) (*requestBinding, error) {
+	binding := &requestBinding{typ: t, hasBody: true}
+	if t.Kind() != reflect.Struct {
+		if !methodHasBody(method) {
+			return nil, fmt.Errorf("%s requests have no body to decode into %s", method, t)
+		}
+		return binding, nil
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if name, ok := field.Tag.Lookup(pathTag); ok {
+			binding.parameters = append(binding.parameters, boundParameter{in: pathParamsType, name: name, field: field})
+			continue
+		}
+		if name, ok := field.Tag.Lookup(queryTag); ok {
+			binding.parameters = append(binding.parameters, boundParameter{in: queryParamType, name: name, field: field})
+			continue
+		}
+		if field.Name == bodyFieldName {
+			binding.body = field.Index
+		}
+	}
+
+	if len(binding.parameters) > 0 || binding.body != nil {
+		binding.hasBody = binding.body != nil
+	} else if t.NumField() == 0 {
+		binding.hasBody = false
+	} else if !methodHasBody(method) {
+		// Requests without body are bound from the query, field by field
+		binding.hasBody = false
+		for _, f := range promotedFields(t, "json") {
+			field := f.field
+			field.Index = f.index
+			binding.parameters = append(binding.parameters, boundParameter{in: queryParamType, name: f.name, field: field})
+		}
+	}
+
+	for _, param := range binding.parameters {
+		if param.name == "" {
+			return nil, fmt.Errorf("field %s of %s has an empty %s tag", param.field.Name, t, param.in)
+		}
+		if !isBindableType(param.field.Type) {
+			return nil, fmt.Errorf("field %s of %s has unsupported type %s", param.field.Name, t, param.field.Type)
+		}
+	}
+	return binding, nil
+}
+
+// methodHasBody reports whether the requests of method are expected to have
+// a body, which is not the case of GET, HEAD and DELETE requests.
+func methodHasBody(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return false
+	}
+	return true
+}
+
+// definitions merges the parameters and the request body described by the
+// binding into schema. Definitions already present in schema are preserved.
+func (b *requestBinding) definitions(schema Definitions) Definitions {
+	params := map[string]ParameterValue{
+		pathParamsType: copyParameterValue(schema.PathParams),
+		queryParamType: copyParameterValue(schema.Querystring),
+	}
+	for _, param := range b.parameters {
+		if _, exists := params[param.in][param.name]; !exists {
+			params[param.in][param.name] = parameterFromField(param.field)
+		}
+	}
+	if len(params[pathParamsType]) > 0 {
+		schema.PathParams = params[pathParamsType]
+	}
+	if len(params[queryParamType]) > 0 {
+		schema.Querystring = params[queryParamType]
+	}
+
+	if b.hasBody && schema.RequestBody == nil {
+		bodyType := b.typ
+		if b.body != nil {
+			bodyType = b.typ.FieldByIndex(b.body).Type
+		}
+		schema.RequestBody = &ContentValue{
+			Content: Content{
+				jsonType: {Value: reflect.Zero(bodyType).Interface()},
+			},
+			Required: true,
+		}
+	}
+
+	return schema
+}
+
+func copyParameterValue(params ParameterValue) ParameterValue {
+	copied := make(ParameterValue, len(params))
+	for name, param := range params {
+		copied[name] = param
+	}
+	return copied
+}
+
+// parameterFromField returns the Parameter documenting a struct field.
+// The field is reflected with its tags, which give the constraints of the
+// schema, the description and the required flag of the parameter.
+func parameterFromField(field reflect.StructField) Parameter {
+	return Parameter{
+		Schema: &Schema{Value: reflect.Zero(field.Type).Interface(), field: &field},
+	}
+}
+
+// bind decodes req into v.
+func (b *requestBinding) bind(v reflect.Value, req *http.Request, pathParams map[string]string) error {
+	validationErr := &RequestValidationError{
+		Message: "request binding failed",
+	}
+
+	query := req.URL.Query()
+	for _, param := range b.parameters {
+		var values []string
+		switch param.in {
+		case pathParamsType:
+			if value, ok := pathParams[param.name]; ok {
+				values = []string{value}
+			}
+		case queryParamType:
+			values = query[param.name]
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		field, ok := fieldByIndex(v, param.field.Index)
+		if !ok {
+			continue
+		}
+		if err := bindValue(field, values); err != nil {
+			validationErr.Errors = append(validationErr.Errors, RequestValidationIssue{
+				In:     param.in,
+				Name:   param.name,
+				Reason: err.Error(),
+			})
+		}
+	}
+
+	if b.hasBody {
+		body := v
+		if b.body != nil {
+			body = v.FieldByIndex(b.body)
+		}
+		if err := json.NewDecoder(req.Body).Decode(body.Addr().Interface()); err != nil {
+			reason := err.Error()
+			if errors.Is(err, io.EOF) {
+				reason = "request body is required"
+			}
+			validationErr.Errors = append(validationErr.Errors, RequestValidationIssue{
+				In:     "body",
+				Reason: reason,
+			})
+		}
+	}
+
+	if len(validationErr.Errors) > 0 {
+		return validationErr
+	}
+	return nil
+}
+
+// fieldByIndex returns the nested field of v at index, allocating the nil
+// embedded pointers on the way. As in encoding/json, the fields of nil
+// pointers to unexported structs can not be set.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// isBindableType reports whether a parameter value can be decoded into t.
+func isBindableType(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return isBindableType(t.Elem())
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Slice && isBindableType(t.Elem())
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// bindValue decodes the parameter values into v.
+func bindValue(v reflect.Value, values []string) error {
+	if unmarshaler, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(values[0]))
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if err := bindValue(elem.Elem(), values); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Slice:
+		slice := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := bindValue(slice.Index(i), []string{value}); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+
+	value := values[0]
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("value %s: an invalid boolean", value)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("value %s: an invalid integer", value)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("value %s: an invalid unsigned integer", value)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("value %s: an invalid number", value)
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
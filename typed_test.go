@@ -0,0 +1,316 @@
+package swagger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.lumeweb.com/gswagger/support/gorilla"
+)
+
+type typedUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type typedUpdateUserRequest struct {
+	UserID int      `path:"userId" jsonschema:"description=The user id"`
+	Notify bool     `query:"notify"`
+	Tags   []string `query:"tag"`
+	Body   typedUser
+}
+
+type typedPage struct {
+	Limit int `json:"limit"`
+}
+
+type typedNotFoundError struct {
+	Message string `json:"message"`
+}
+
+func (e typedNotFoundError) Error() string   { return e.Message }
+func (e typedNotFoundError) StatusCode() int { return http.StatusNotFound }
+
+func TestAddTypedRoute(t *testing.T) {
+	setup := func(t *testing.T) (*TestRouter, *mux.Router) {
+		t.Helper()
+
+		muxRouter := mux.NewRouter()
+		router, err := NewRouter(gorilla.NewRouter(muxRouter), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Context: context.Background(),
+			Openapi: getBaseSwagger(t),
+		})
+		require.NoError(t, err)
+		return router, muxRouter
+	}
+
+	t.Run("binds path, query and body", func(t *testing.T) {
+		router, muxRouter := setup(t)
+
+		var received typedUpdateUserRequest
+		_, err := AddTypedRoute(router, http.MethodPut, "/users/{userId}", func(ctx context.Context, req typedUpdateUserRequest) (typedUser, error) {
+			received = req
+			if req.UserID == 404 {
+				return typedUser{}, typedNotFoundError{Message: "user not found"}
+			}
+			return typedUser{ID: req.UserID, Name: req.Body.Name}, nil
+		}, Definitions{})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/users/42?notify=true&tag=a&tag=b", strings.NewReader(`{"name":"Jane"}`))
+		muxRouter.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, jsonType, w.Header().Get("Content-Type"))
+		require.JSONEq(t, `{"id":42,"name":"Jane"}`, w.Body.String())
+		require.Equal(t, typedUpdateUserRequest{
+			UserID: 42,
+			Notify: true,
+			Tags:   []string{"a", "b"},
+			Body:   typedUser{Name: "Jane"},
+		}, received)
+
+		t.Run("binding errors are reported as 400", func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/users/foo?notify=maybe", strings.NewReader(`{`))
+			muxRouter.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+
+			var validationErr RequestValidationError
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &validationErr))
+			require.Equal(t, "request binding failed", validationErr.Message)
+			require.Len(t, validationErr.Errors, 3)
+			require.Equal(t, RequestValidationIssue{In: "path", Name: "userId", Reason: "value foo: an invalid integer"}, validationErr.Errors[0])
+			require.Equal(t, RequestValidationIssue{In: "query", Name: "notify", Reason: "value maybe: an invalid boolean"}, validationErr.Errors[1])
+			require.Equal(t, "body", validationErr.Errors[2].In)
+		})
+
+		t.Run("handler errors are written with their status code", func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/users/404", strings.NewReader(`{"name":"Jane"}`))
+			muxRouter.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusNotFound, w.Code)
+			require.JSONEq(t, `{"message":"user not found"}`, w.Body.String())
+		})
+	})
+
+	t.Run("internal errors are not sent to the client", func(t *testing.T) {
+		var reported []error
+		muxRouter := mux.NewRouter()
+		router, err := NewRouter(gorilla.NewRouter(muxRouter), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Context: context.Background(),
+			Openapi: getBaseSwagger(t),
+			ErrorHandler: func(req *http.Request, err error) {
+				require.Equal(t, "/users/1", req.URL.Path)
+				reported = append(reported, err)
+			},
+		})
+		require.NoError(t, err)
+
+		handlerErr := errors.New("connection refused: db.internal:5432")
+		_, err = AddTypedRoute(router, http.MethodGet, "/users/{userId}", func(ctx context.Context, req struct {
+			UserID int `path:"userId"`
+		}) (typedUser, error) {
+			if req.UserID == 1 {
+				return typedUser{}, handlerErr
+			}
+			return typedUser{}, typedNotFoundError{Message: "user not found"}
+		}, Definitions{})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		muxRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+		require.JSONEq(t, `{"message":"Internal Server Error"}`, w.Body.String())
+		require.Equal(t, []error{handlerErr}, reported)
+
+		w = httptest.NewRecorder()
+		muxRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/2", nil))
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.Len(t, reported, 1)
+	})
+
+	t.Run("documents the route from the types", func(t *testing.T) {
+		router, _ := setup(t)
+
+		_, err := AddTypedRoute(router, http.MethodPut, "/users/{userId}", func(ctx context.Context, req typedUpdateUserRequest) (typedUser, error) {
+			return typedUser{}, nil
+		}, Definitions{
+			Querystring: ParameterValue{
+				"notify": {Schema: &Schema{Value: false}, Description: "Send a notification"},
+			},
+		})
+		require.NoError(t, err)
+
+		operation := router.swaggerSchema.Paths.Value("/users/{userId}").Put
+		require.NotNil(t, operation)
+
+		params := map[string]string{}
+		for _, param := range operation.Parameters {
+			params[param.Value.In+":"+param.Value.Name] = param.Value.Description
+		}
+		require.Equal(t, map[string]string{
+			"path:userId":  "The user id",
+			"query:notify": "Send a notification",
+			"query:tag":    "",
+		}, params)
+
+		require.NotNil(t, operation.RequestBody)
+		require.True(t, operation.RequestBody.Value.Required)
+		require.NotNil(t, operation.RequestBody.Value.Content.Get(jsonType))
+		require.NotNil(t, operation.Responses.Status(http.StatusOK).Value.Content.Get(jsonType))
+	})
+
+	t.Run("parameters keep the constraints of their tags", func(t *testing.T) {
+		router, _ := setup(t)
+
+		type request struct {
+			UserID int    `path:"userId" jsonschema:"minimum=1"`
+			Fields string `query:"fields" jsonschema:"enum=id,enum=name,default=id"`
+		}
+		_, err := AddTypedRoute(router, http.MethodGet, "/users/{userId}", func(ctx context.Context, req request) (typedUser, error) {
+			return typedUser{}, nil
+		}, Definitions{})
+		require.NoError(t, err)
+
+		parameters, err := json.Marshal(router.swaggerSchema.Paths.Value("/users/{userId}").Get.Parameters)
+		require.NoError(t, err)
+		require.JSONEq(t, `[
+			{"in": "path", "name": "userId", "required": true, "schema": {"type": "integer", "minimum": 1}},
+			{"in": "query", "name": "fields", "schema": {"type": "string", "enum": ["id", "name"], "default": "id"}}
+		]`, string(parameters))
+	})
+
+	t.Run("uses the documented success status", func(t *testing.T) {
+		router, muxRouter := setup(t)
+
+		_, err := AddTypedRoute(router, http.MethodPost, "/users", func(ctx context.Context, req typedUser) (typedUser, error) {
+			return req, nil
+		}, Definitions{
+			Responses: map[int]ContentValue{
+				http.StatusCreated:    {Description: "Created"},
+				http.StatusBadRequest: {Content: Content{jsonType: {Value: RequestValidationError{}}}},
+			},
+		})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		muxRouter.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"id":1,"name":"Jane"}`)))
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.JSONEq(t, `{"id":1,"name":"Jane"}`, w.Body.String())
+
+		response := router.swaggerSchema.Paths.Value("/users").Post.Responses.Status(http.StatusCreated)
+		require.Equal(t, "Created", *response.Value.Description)
+		require.NotNil(t, response.Value.Content.Get(jsonType))
+	})
+
+	t.Run("no content response has no body", func(t *testing.T) {
+		router, muxRouter := setup(t)
+
+		_, err := AddTypedRoute(router, http.MethodPut, "/users", func(ctx context.Context, req typedUser) (typedUser, error) {
+			return req, nil
+		}, Definitions{
+			Responses: map[int]ContentValue{http.StatusNoContent: {Description: "Updated"}},
+		})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		muxRouter.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/users", strings.NewReader(`{"id":1,"name":"Jane"}`)))
+		require.Equal(t, http.StatusNoContent, w.Code)
+		require.Empty(t, w.Body.String())
+		require.Empty(t, w.Header().Get("Content-Type"))
+
+		response := router.swaggerSchema.Paths.Value("/users").Put.Responses.Status(http.StatusNoContent)
+		require.Equal(t, "Updated", *response.Value.Description)
+		require.Empty(t, response.Value.Content)
+	})
+
+	t.Run("empty request has no body", func(t *testing.T) {
+		router, muxRouter := setup(t)
+
+		_, err := AddTypedRoute(router, http.MethodGet, "/ping", func(ctx context.Context, req struct{}) (string, error) {
+			return "pong", nil
+		}, Definitions{})
+		require.NoError(t, err)
+		require.Nil(t, router.swaggerSchema.Paths.Value("/ping").Get.RequestBody)
+
+		w := httptest.NewRecorder()
+		muxRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `"pong"`, w.Body.String())
+	})
+
+	t.Run("requests without body are bound from the query", func(t *testing.T) {
+		router, muxRouter := setup(t)
+
+		type request struct {
+			typedPage
+			Search string `json:"search"`
+			Ignore string `json:"-"`
+		}
+		var received request
+		_, err := AddTypedRoute(router, http.MethodGet, "/users", func(ctx context.Context, req request) ([]typedUser, error) {
+			received = req
+			return []typedUser{}, nil
+		}, Definitions{})
+		require.NoError(t, err)
+
+		operation := router.swaggerSchema.Paths.Value("/users").Get
+		require.Nil(t, operation.RequestBody)
+		require.Len(t, operation.Parameters, 2)
+		require.NotNil(t, operation.Parameters.GetByInAndName(queryParamType, "limit"))
+		require.NotNil(t, operation.Parameters.GetByInAndName(queryParamType, "search"))
+
+		w := httptest.NewRecorder()
+		muxRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?limit=10&search=jane", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, 10, received.Limit)
+		require.Equal(t, "jane", received.Search)
+
+		w = httptest.NewRecorder()
+		muxRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("requests without body can not be decoded from the body", func(t *testing.T) {
+		router, _ := setup(t)
+
+		_, err := AddTypedRoute(router, http.MethodDelete, "/users", func(ctx context.Context, req []int) (string, error) {
+			return "", nil
+		}, Definitions{})
+		require.ErrorIs(t, err, ErrTypedRoute)
+	})
+
+	t.Run("unsupported parameter type", func(t *testing.T) {
+		router, _ := setup(t)
+
+		type request struct {
+			Filter map[string]string `query:"filter"`
+		}
+		_, err := AddTypedRoute(router, http.MethodGet, "/items", func(ctx context.Context, req request) (string, error) {
+			return "", nil
+		}, Definitions{})
+		require.ErrorIs(t, err, ErrTypedRoute)
+	})
+
+	t.Run("router without binding support", func(t *testing.T) {
+		router, err := NewRouter[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route](notValidatingRouter{gorilla.NewRouter(mux.NewRouter())}, Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Openapi: getBaseSwagger(t),
+		})
+		require.NoError(t, err)
+
+		_, err = AddTypedRoute(router, http.MethodGet, "/ping", func(ctx context.Context, req struct{}) (string, error) {
+			return "pong", nil
+		}, Definitions{})
+		require.ErrorIs(t, err, ErrTypedRouteNotSupported)
+	})
+}
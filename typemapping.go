@@ -0,0 +1,156 @@
+package swagger
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/netip"
+	"reflect"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/jsonschema"
+)
+
+// defaultTypeMappings documents the standard library types which are
+// reflected poorly. time.Duration is encoded by encoding/json as an int64
+// number of nanoseconds.
+var defaultTypeMappings = map[reflect.Type]*openapi3.Schema{
+	reflect.TypeFor[time.Duration]():   {Type: &openapi3.Types{openapi3.TypeInteger}, Format: "int64", Description: "Duration in nanoseconds"},
+	reflect.TypeFor[[]byte]():          {Type: &openapi3.Types{openapi3.TypeString}, Format: "byte"},
+	reflect.TypeFor[json.RawMessage](): {},
+	reflect.TypeFor[net.IP]():          {Type: &openapi3.Types{openapi3.TypeString}},
+	reflect.TypeFor[netip.Addr]():      {Type: &openapi3.Types{openapi3.TypeString}},
+	reflect.TypeFor[netip.AddrPort]():  {Type: &openapi3.Types{openapi3.TypeString}},
+	reflect.TypeFor[netip.Prefix]():    {Type: &openapi3.Types{openapi3.TypeString}},
+	reflect.TypeFor[sql.NullString]():  {Type: &openapi3.Types{openapi3.TypeString}, Nullable: true},
+	reflect.TypeFor[sql.NullInt64]():   {Type: &openapi3.Types{openapi3.TypeInteger}, Format: "int64", Nullable: true},
+	reflect.TypeFor[sql.NullInt32]():   {Type: &openapi3.Types{openapi3.TypeInteger}, Format: "int32", Nullable: true},
+	reflect.TypeFor[sql.NullInt16]():   {Type: &openapi3.Types{openapi3.TypeInteger}, Nullable: true},
+	reflect.TypeFor[sql.NullByte]():    {Type: &openapi3.Types{openapi3.TypeInteger}, Nullable: true},
+	reflect.TypeFor[sql.NullFloat64](): {Type: &openapi3.Types{openapi3.TypeNumber}, Format: "double", Nullable: true},
+	reflect.TypeFor[sql.NullBool]():    {Type: &openapi3.Types{openapi3.TypeBoolean}, Nullable: true},
+	reflect.TypeFor[sql.NullTime]():    {Type: &openapi3.Types{openapi3.TypeString}, Format: "date-time", Nullable: true},
+}
+
+// wellKnownTypeMappings documents the types of third party packages,
+// identified by their package path and name, without depending on them.
+var wellKnownTypeMappings = map[string]*openapi3.Schema{
+	"github.com/google/uuid.UUID":               {Type: &openapi3.Types{openapi3.TypeString}, Format: "uuid"},
+	"github.com/gofrs/uuid.UUID":                {Type: &openapi3.Types{openapi3.TypeString}, Format: "uuid"},
+	"github.com/gofrs/uuid/v5.UUID":             {Type: &openapi3.Types{openapi3.TypeString}, Format: "uuid"},
+	"github.com/satori/go.uuid.UUID":            {Type: &openapi3.Types{openapi3.TypeString}, Format: "uuid"},
+	"github.com/shopspring/decimal.Decimal":     {Type: &openapi3.Types{openapi3.TypeString}, Format: "decimal"},
+	"github.com/shopspring/decimal.NullDecimal": {Type: &openapi3.Types{openapi3.TypeString}, Format: "decimal", Nullable: true},
+}
+
+// typeDescriptionKeyword holds the description of a mapped type in a
+// reflected schema, until it is applied by applyTypeDescription.
+const typeDescriptionKeyword = "x-gswagger-type-description"
+
+// typeMappings holds the schemas documenting Go types, as JSON objects.
+type typeMappings struct {
+	types map[reflect.Type]map[string]any
+	names map[string]map[string]any
+}
+
+// newTypeMappings returns the default type mappings, overridden by mappings.
+// A nil schema restores the reflection of a type.
+func newTypeMappings(mappings map[reflect.Type]*openapi3.Schema) (typeMappings, error) {
+	result := typeMappings{
+		types: make(map[reflect.Type]map[string]any, len(defaultTypeMappings)+len(mappings)),
+		names: make(map[string]map[string]any, len(wellKnownTypeMappings)),
+	}
+	for name, schema := range wellKnownTypeMappings {
+		object, err := schemaObject(schema)
+		if err != nil {
+			return typeMappings{}, fmt.Errorf("invalid type mapping for %s: %w", name, err)
+		}
+		result.names[name] = object
+	}
+	for _, m := range []map[reflect.Type]*openapi3.Schema{defaultTypeMappings, mappings} {
+		for t, schema := range m {
+			if t == nil {
+				return typeMappings{}, fmt.Errorf("invalid type mapping: nil type")
+			}
+			if schema == nil {
+				result.types[t] = nil
+				continue
+			}
+			object, err := schemaObject(schema)
+			if err != nil {
+				return typeMappings{}, fmt.Errorf("invalid type mapping for %s: %w", t, err)
+			}
+			result.types[t] = object
+		}
+	}
+	return result, nil
+}
+
+// schemaObject returns the JSON object of schema.
+func schemaObject(schema *openapi3.Schema) (map[string]any, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	object := map[string]any{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// schema returns the schema documenting t, or nil if t is reflected.
+func (m typeMappings) schema(t reflect.Type) map[string]any {
+	if object, ok := m.types[t]; ok {
+		return object
+	}
+	if t.Name() == "" {
+		return nil
+	}
+	return m.names[t.PkgPath()+"."+t.Name()]
+}
+
+// typeMapper returns a jsonschema Mapper documenting the mapped types with
+// their fixed schema, wrapping mapper.
+func typeMapper(mapper func(reflect.Type) *jsonschema.Schema, mappings typeMappings) func(reflect.Type) *jsonschema.Schema {
+	return func(t reflect.Type) *jsonschema.Schema {
+		if mapper != nil {
+			if schema := mapper(t); schema != nil {
+				return schema
+			}
+		}
+		object := mappings.schema(t)
+		if object == nil {
+			return nil
+		}
+		// The schema is written as it is, an empty schema included
+		extras := make(map[string]any, len(object))
+		for keyword, value := range object {
+			extras[keyword] = value
+		}
+		// The reflector replaces the description of a field schema with the
+		// description of the field, so the description of the type is moved
+		// to a keyword applied once the schema is reflected
+		if description, ok := extras["description"]; ok {
+			extras[typeDescriptionKeyword] = description
+			delete(extras, "description")
+		}
+		return &jsonschema.Schema{Extras: extras}
+	}
+}
+
+// applyTypeDescription sets the description of a reflected schema to the
+// description of its mapped type, unless the field documented by the schema
+// has its own description.
+func applyTypeDescription(schema map[string]any) {
+	description, ok := schema[typeDescriptionKeyword]
+	if !ok {
+		return
+	}
+	delete(schema, typeDescriptionKeyword)
+	if schema["description"] == nil {
+		schema["description"] = description
+	}
+}
@@ -0,0 +1,117 @@
+package swagger
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.lumeweb.com/gswagger/support/gorilla"
+)
+
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type Job struct {
+	Timeout time.Duration `json:"timeout" jsonschema:"description=Maximum run time in nanoseconds"`
+}
+
+type Payment struct {
+	ID        uuid.UUID       `json:"id"`
+	Timeout   time.Duration   `json:"timeout"`
+	Signature []byte          `json:"signature"`
+	Metadata  json.RawMessage `json:"metadata"`
+	Client    netip.Addr      `json:"client"`
+	Note      sql.NullString  `json:"note"`
+	Count     sql.NullInt64   `json:"count"`
+	PaidAt    sql.NullTime    `json:"paidAt"`
+	Total     Money           `json:"total"`
+}
+
+func TestTypeMappings(t *testing.T) {
+	setup := func(t *testing.T, mappings map[reflect.Type]*openapi3.Schema) *TestRouter {
+		t.Helper()
+
+		router, err := NewRouter(gorilla.NewRouter(mux.NewRouter()), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Openapi:      getBaseSwagger(t),
+			TypeMappings: mappings,
+		})
+		require.NoError(t, err)
+		return router
+	}
+	properties := func(t *testing.T, router *TestRouter, v any) string {
+		t.Helper()
+
+		schema, err := router.getSchemaFromInterface(v, false)
+		require.NoError(t, err)
+		data, err := json.Marshal(router.GetSwaggerSchema().Components.Schemas[schema.Ref[len("#/$defs/"):]].Value.Properties)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("well-known types have default mappings", func(t *testing.T) {
+		router := setup(t, nil)
+
+		require.JSONEq(t, `{
+			"id": {"type": "string", "format": "uuid"},
+			"timeout": {"type": "integer", "format": "int64", "description": "Duration in nanoseconds"},
+			"signature": {"type": "string", "format": "byte"},
+			"metadata": {},
+			"client": {"type": "string"},
+			"note": {"type": "string", "nullable": true},
+			"count": {"type": "integer", "format": "int64", "nullable": true},
+			"paidAt": {"type": "string", "format": "date-time", "nullable": true},
+			"total": {"$ref": "#/$defs/Money"}
+		}`, properties(t, router, Payment{}))
+	})
+
+	t.Run("field descriptions override the description of the type", func(t *testing.T) {
+		router := setup(t, nil)
+
+		require.JSONEq(t, `{
+			"timeout": {"type": "integer", "format": "int64", "description": "Maximum run time in nanoseconds"}
+		}`, properties(t, router, Job{}))
+	})
+
+	t.Run("custom mappings override the defaults", func(t *testing.T) {
+		router := setup(t, map[reflect.Type]*openapi3.Schema{
+			reflect.TypeFor[Money]():         openapi3.NewStringSchema().WithPattern(`^\d+\.\d{2} [A-Z]{3}$`),
+			reflect.TypeFor[time.Duration](): openapi3.NewStringSchema().WithFormat("duration"),
+			reflect.TypeFor[uuid.UUID]():     nil,
+		})
+
+		schema, err := router.getSchemaFromInterface(Money{}, false)
+		require.NoError(t, err)
+		require.Equal(t, `^\d+\.\d{2} [A-Z]{3}$`, schema.Value.Pattern)
+
+		require.JSONEq(t, `{
+			"id": {"$ref": "#/$defs/UUID"},
+			"timeout": {"type": "string", "format": "duration"},
+			"signature": {"type": "string", "format": "byte"},
+			"metadata": {},
+			"client": {"type": "string"},
+			"note": {"type": "string", "nullable": true},
+			"count": {"type": "integer", "format": "int64", "nullable": true},
+			"paidAt": {"type": "string", "format": "date-time", "nullable": true},
+			"total": {"type": "string", "pattern": "^\\d+\\.\\d{2} [A-Z]{3}$"}
+		}`, properties(t, router, Payment{}))
+	})
+
+	t.Run("openapi 3.1", func(t *testing.T) {
+		router := setup(t, nil)
+		router.GetSwaggerSchema().OpenAPI = OpenapiVersion31
+
+		schema, err := router.getSchemaFromInterface(sql.NullBool{}, false)
+		require.NoError(t, err)
+		require.True(t, schema.Value.Nullable)
+		require.Equal(t, &openapi3.Types{openapi3.TypeBoolean}, schema.Value.Type)
+	})
+}
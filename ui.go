@@ -0,0 +1,74 @@
+package swagger
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path"
+)
+
+// DefaultUIDocumentationPath is the suggested path of the documentation UI.
+const DefaultUIDocumentationPath = "/documentation"
+
+//go:embed ui
+var uiFS embed.FS
+
+// uiAssets are the embedded assets loaded by the documentation UI page: the
+// Swagger UI bundle and stylesheet, and the script rendering the documentation.
+var uiAssets = []struct {
+	name        string
+	contentType string
+}{
+	{name: "swagger-ui.css", contentType: "text/css; charset=utf-8"},
+	{name: "swagger-ui-bundle.js", contentType: "text/javascript; charset=utf-8"},
+	{name: "ui.js", contentType: "text/javascript; charset=utf-8"},
+}
+
+var uiIndexTemplate = template.Must(template.ParseFS(uiFS, "ui/index.html"))
+
+// uiDocumentationPaths returns the paths of the documentation UI page and of
+// its assets, without the router path prefix. Empty if the UI is disabled.
+func (r *Router[_, _, _]) uiDocumentationPaths() []string {
+	if r.options.uiDocumentationPath == "" {
+		return nil
+	}
+
+	paths := []string{r.options.uiDocumentationPath}
+	for _, asset := range uiAssets {
+		paths = append(paths, path.Join(r.options.uiDocumentationPath, asset.name))
+	}
+	return paths
+}
+
+// exposeDocumentationUI registers the documentation UI page and its assets.
+// The page loads the JSON documentation exposed by the router.
+func (r *Router[_, _, _]) exposeDocumentationUI() error {
+	title := "API documentation"
+	if r.swaggerSchema.Info != nil && r.swaggerSchema.Info.Title != "" {
+		title = r.swaggerSchema.Info.Title
+	}
+
+	var index bytes.Buffer
+	err := uiIndexTemplate.Execute(&index, map[string]string{
+		"Title":         title,
+		"SpecURL":       path.Join("/", r.pathPrefix, r.jsonDocumentationPath),
+		"StylesheetURL": path.Join("/", r.pathPrefix, r.options.uiDocumentationPath, "swagger-ui.css"),
+		"BundleURL":     path.Join("/", r.pathPrefix, r.options.uiDocumentationPath, "swagger-ui-bundle.js"),
+		"ScriptURL":     path.Join("/", r.pathPrefix, r.options.uiDocumentationPath, "ui.js"),
+	})
+	if err != nil {
+		return err
+	}
+	r.router.AddRoute(http.MethodGet, r.options.uiDocumentationPath, r.router.SwaggerHandler("text/html; charset=utf-8", index.Bytes()))
+
+	for _, asset := range uiAssets {
+		content, err := uiFS.ReadFile(path.Join("ui", asset.name))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", asset.name, err)
+		}
+		r.router.AddRoute(http.MethodGet, path.Join(r.options.uiDocumentationPath, asset.name), r.router.SwaggerHandler(asset.contentType, content))
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+(function () {
+  "use strict";
+
+  var root = document.getElementById("swagger-ui");
+
+  window.ui = SwaggerUIBundle({
+    url: root.getAttribute("data-spec-url"),
+    domNode: root,
+    deepLinking: true,
+    validatorUrl: null,
+    presets: [SwaggerUIBundle.presets.apis],
+    layout: "BaseLayout"
+  });
+})();
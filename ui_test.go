@@ -0,0 +1,131 @@
+package swagger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.lumeweb.com/gswagger/support/gorilla"
+)
+
+func TestDocumentationUI(t *testing.T) {
+	doRequest := func(router http.Handler, host, path string) *http.Response {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if host != "" {
+			req.Host = host
+		}
+		router.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	t.Run("exposes the UI and its embedded assets", func(t *testing.T) {
+		muxRouter := mux.NewRouter()
+		router, err := NewRouter(gorilla.NewRouter(muxRouter), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Context:             context.Background(),
+			Openapi:             getBaseSwagger(t),
+			UIDocumentationPath: DefaultUIDocumentationPath,
+		})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		resp := doRequest(router, "", "/documentation")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+		body := readBody(t, resp.Body)
+		require.Contains(t, body, "<title>test openapi title</title>")
+		require.Contains(t, body, `data-spec-url="/documentation/json"`)
+		require.Contains(t, body, `href="/documentation/swagger-ui.css"`)
+		require.Contains(t, body, `src="/documentation/swagger-ui-bundle.js"`)
+		require.Contains(t, body, `src="/documentation/ui.js"`)
+		require.NotContains(t, body, "http://")
+		require.NotContains(t, body, "https://")
+
+		resp = doRequest(router, "", "/documentation/ui.js")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "text/javascript; charset=utf-8", resp.Header.Get("Content-Type"))
+		require.NotEmpty(t, readBody(t, resp.Body))
+
+		resp = doRequest(router, "", "/documentation/swagger-ui-bundle.js")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "text/javascript; charset=utf-8", resp.Header.Get("Content-Type"))
+		require.Contains(t, readBody(t, resp.Body), "SwaggerUIBundle")
+
+		resp = doRequest(router, "", "/documentation/swagger-ui.css")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "text/css; charset=utf-8", resp.Header.Get("Content-Type"))
+
+		resp = doRequest(router, "", DefaultJSONDocumentationPath)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("respects the path prefix", func(t *testing.T) {
+		muxRouter := mux.NewRouter()
+		router, err := NewRouter(gorilla.NewRouter(muxRouter), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Context:             context.Background(),
+			Openapi:             getBaseSwagger(t),
+			PathPrefix:          "/api",
+			UIDocumentationPath: "/docs",
+		})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		resp := doRequest(router, "", "/api/docs")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body := readBody(t, resp.Body)
+		require.Contains(t, body, `data-spec-url="/api/documentation/json"`)
+		require.Contains(t, body, `src="/api/docs/ui.js"`)
+
+		resp = doRequest(router, "", "/api/docs/ui.js")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = doRequest(router, "", "/docs")
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("host routers without schema serve the root UI", func(t *testing.T) {
+		router, err := NewRouter(gorilla.NewRouter(mux.NewRouter()), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Context:             context.Background(),
+			Openapi:             getBaseSwagger(t),
+			UIDocumentationPath: "/docs",
+			FrameworkRouterFactory: func() gorillaAPIRouter {
+				return gorilla.NewRouter(mux.NewRouter())
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = router.Host("api.example.com:80")
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		resp := doRequest(router, "api.example.com", "/docs")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Contains(t, readBody(t, resp.Body), `data-spec-url="/documentation/json"`)
+
+		resp = doRequest(router, "api.example.com", "/docs/swagger-ui.css")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("is disabled by default", func(t *testing.T) {
+		router, err := NewRouter(gorilla.NewRouter(mux.NewRouter()), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Context: context.Background(),
+			Openapi: getBaseSwagger(t),
+		})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		resp := doRequest(router, "", DefaultUIDocumentationPath)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("invalid path", func(t *testing.T) {
+		_, err := NewRouter(gorilla.NewRouter(mux.NewRouter()), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Openapi:             getBaseSwagger(t),
+			UIDocumentationPath: "docs",
+		})
+		require.EqualError(t, err, "invalid path docs. Path should start with '/'")
+	})
+}
@@ -0,0 +1,348 @@
+package swagger
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"go.lumeweb.com/gswagger/apirouter"
+)
+
+// ErrValidationNotSupported is returned when request or response validation is
+// enabled but the framework router does not implement apirouter.ValidatingRouter
+// or apirouter.ResponseValidatingRouter.
+var ErrValidationNotSupported = errors.New("router does not support validation")
+
+// ResponseValidationMode configures how the responses written by route handlers
+// are checked against the documented responses.
+type ResponseValidationMode int
+
+const (
+	// ResponseValidationDisabled does not check responses.
+	ResponseValidationDisabled ResponseValidationMode = iota
+	// ResponseValidationLog reports invalid responses to the error handler,
+	// and sends them to the client unchanged.
+	ResponseValidationLog
+	// ResponseValidationFail reports invalid responses to the error handler,
+	// and replaces them with a 500 ResponseValidationError.
+	ResponseValidationFail
+)
+
+// RequestValidationError is returned to the client when a request does not
+// match the operation documented for its route.
+type RequestValidationError struct {
+	Message string                   `json:"message"`
+	Errors  []RequestValidationIssue `json:"errors,omitempty"`
+}
+
+// RequestValidationIssue describes a single invalid part of a request.
+type RequestValidationIssue struct {
+	In     string `json:"in"`             // Location (path, query, header, cookie, body)
+	Name   string `json:"name,omitempty"` // Parameter name, empty for the body
+	Reason string `json:"reason"`         // Human-readable reason
+}
+
+func (e *RequestValidationError) Error() string {
+	reasons := make([]string, 0, len(e.Errors))
+	for _, issue := range e.Errors {
+		if issue.Name != "" {
+			reasons = append(reasons, fmt.Sprintf("%s %q: %s", issue.In, issue.Name, issue.Reason))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", issue.In, issue.Reason))
+		}
+	}
+	if len(reasons) == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Message, strings.Join(reasons, "; "))
+}
+
+// StatusCode implements apirouter.HTTPError.
+func (e *RequestValidationError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// ResponseValidationError reports a response which does not match the
+// responses documented for its route.
+type ResponseValidationError struct {
+	Message string   `json:"message"`
+	Method  string   `json:"method"`
+	Path    string   `json:"path"`
+	Status  int      `json:"status"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+func (e *ResponseValidationError) Error() string {
+	return fmt.Sprintf("%s for %s %s with status %d: %s", e.Message, e.Method, e.Path, e.Status, strings.Join(e.Errors, "; "))
+}
+
+// StatusCode implements apirouter.HTTPError.
+func (e *ResponseValidationError) StatusCode() int {
+	return http.StatusInternalServerError
+}
+
+// validationRoute lazily builds the openapi3filter route of an operation.
+// References in the operation are resolved on first use, so that components
+// registered after the route are available. The resolution updates the
+// components shared by every route, so it holds the write lock of the
+// references, while validations hold its read lock.
+type validationRoute struct {
+	once  sync.Once
+	lock  *sync.RWMutex
+	route *routers.Route
+	err   error
+	build func() (*routers.Route, error)
+}
+
+// validate calls validate with the openapi3filter route of the operation.
+func (v *validationRoute) validate(validate func(route *routers.Route) error) error {
+	v.once.Do(func() {
+		v.lock.Lock()
+		defer v.lock.Unlock()
+		v.route, v.err = v.build()
+	})
+	if v.err != nil {
+		return fmt.Errorf("%w: %s", ErrGenerateOAS, v.err)
+	}
+
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	return validate(v.route)
+}
+
+func (r *Router[_, _, _]) newValidationRoute(method, oasPath string, operation *openapi3.Operation) *validationRoute {
+	return &validationRoute{
+		lock: r.referencesLock,
+		build: func() (*routers.Route, error) {
+			op := Operation{operation}
+			if err := op.ResolveReferences(r.swaggerSchema); err != nil {
+				return nil, err
+			}
+
+			// Security requirements are not enforced by validation
+			validatedOperation := *operation
+			validatedOperation.Security = openapi3.NewSecurityRequirements()
+
+			pathItem := r.swaggerSchema.Paths.Value(oasPath)
+			if pathItem == nil {
+				pathItem = &openapi3.PathItem{}
+			}
+			return &routers.Route{
+				Spec:      r.swaggerSchema,
+				Path:      oasPath,
+				PathItem:  pathItem,
+				Method:    method,
+				Operation: &validatedOperation,
+			}, nil
+		},
+	}
+}
+
+// newRequestValidator returns an apirouter.RequestValidator checking requests
+// against the operation of the given route.
+func newRequestValidator(route *validationRoute) apirouter.RequestValidator {
+	options := &openapi3filter.Options{
+		MultiError: true,
+	}
+
+	return func(req *http.Request, pathParams map[string]string) error {
+		return route.validate(func(oasRoute *routers.Route) error {
+			input := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      oasRoute,
+				Options:    options,
+			}
+			if err := openapi3filter.ValidateRequest(req.Context(), input); err != nil {
+				return newRequestValidationError(err)
+			}
+			return nil
+		})
+	}
+}
+
+// newResponseValidator returns an apirouter.ResponseValidator checking the
+// responses written by the handler of the given route. Invalid responses are
+// passed to onError, and replaced only in ResponseValidationFail mode.
+func newResponseValidator(route *validationRoute, mode ResponseValidationMode, onError func(req *http.Request, err error)) apirouter.ResponseValidator {
+	options := &openapi3filter.Options{
+		IncludeResponseStatus: true,
+		MultiError:            true,
+	}
+
+	return func(req *http.Request, pathParams map[string]string, status int, header http.Header, body []byte) error {
+		err := route.validate(func(oasRoute *routers.Route) error {
+			input := &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: &openapi3filter.RequestValidationInput{
+					Request:    req,
+					PathParams: pathParams,
+					Route:      oasRoute,
+				},
+				Status:  status,
+				Header:  header,
+				Options: options,
+			}
+			input.SetBodyBytes(body)
+
+			err := openapi3filter.ValidateResponse(req.Context(), input)
+			if err == nil {
+				return nil
+			}
+			validationErr := &ResponseValidationError{
+				Message: "response validation failed",
+				Method:  oasRoute.Method,
+				Path:    oasRoute.Path,
+				Status:  status,
+			}
+			var multiErr openapi3.MultiError
+			if errors.As(err, &multiErr) {
+				for _, e := range multiErr {
+					validationErr.Errors = append(validationErr.Errors, e.Error())
+				}
+			} else {
+				validationErr.Errors = []string{err.Error()}
+			}
+			return validationErr
+		})
+
+		// The error handler is called without holding the lock of the references
+		var validationErr *ResponseValidationError
+		if !errors.As(err, &validationErr) {
+			return err
+		}
+		onError(req, validationErr)
+		if mode == ResponseValidationFail {
+			return validationErr
+		}
+		return nil
+	}
+}
+
+// logResponseValidationError is the default handler of invalid responses.
+func logResponseValidationError(_ *http.Request, err error) {
+	log.Printf("gswagger: %s", err)
+}
+
+func newRequestValidationError(err error) *RequestValidationError {
+	validationErr := &RequestValidationError{
+		Message: "request validation failed",
+	}
+
+	var errs []error
+	var multiErr openapi3.MultiError
+	if errors.As(err, &multiErr) {
+		errs = multiErr
+	} else {
+		errs = []error{err}
+	}
+
+	for _, e := range errs {
+		issue := RequestValidationIssue{
+			In:     "request",
+			Reason: e.Error(),
+		}
+
+		var requestErr *openapi3filter.RequestError
+		if errors.As(e, &requestErr) {
+			issue.Reason = requestErrorReason(requestErr)
+			switch {
+			case requestErr.Parameter != nil:
+				issue.In = requestErr.Parameter.In
+				issue.Name = requestErr.Parameter.Name
+			case requestErr.RequestBody != nil:
+				issue.In = "body"
+			}
+		}
+		validationErr.Errors = append(validationErr.Errors, issue)
+	}
+
+	return validationErr
+}
+
+// requestErrorReason returns the reason of a RequestError without the
+// parameter prefix added by its Error method.
+func requestErrorReason(err *openapi3filter.RequestError) string {
+	reason := err.Reason
+	if err.Err == nil {
+		return reason
+	}
+
+	var schemaErr *openapi3.SchemaError
+	cause := err.Err.Error()
+	if errors.As(err.Err, &schemaErr) {
+		cause = schemaErr.Reason
+		if path := schemaErr.JSONPointer(); len(path) > 0 {
+			cause = fmt.Sprintf("%s (at /%s)", cause, strings.Join(path, "/"))
+		}
+	}
+
+	if reason == "" || reason == cause {
+		return cause
+	}
+	return reason + ": " + cause
+}
+
+// routeMiddleware returns the middleware gswagger adds to every route,
+// according to the router options.
+func (r *Router[HandlerFunc, MiddlewareFunc, Route]) routeMiddleware(method, oasPath string, operation *openapi3.Operation) ([]MiddlewareFunc, error) {
+	var middleware []MiddlewareFunc
+	route := r.newValidationRoute(method, oasPath, operation)
+
+	// Authentication runs before request validation. Routes with security
+	// requirements are authenticated even if their schemes are registered later.
+	if len(r.authenticators) > 0 || hasSecurityRequirements(r.swaggerSchema, operation) {
+		validatingRouter, ok := r.router.(apirouter.ValidatingRouter[MiddlewareFunc])
+		if ok {
+			middleware = append(middleware, validatingRouter.ValidationMiddleware(r.newAuthenticator(operation)))
+		} else if len(r.authenticators) > 0 {
+			return nil, ErrValidationNotSupported
+		}
+	}
+
+	if r.options.validateRequests {
+		validatingRouter, ok := r.router.(apirouter.ValidatingRouter[MiddlewareFunc])
+		if !ok {
+			return nil, ErrValidationNotSupported
+		}
+		middleware = append(middleware, validatingRouter.ValidationMiddleware(newRequestValidator(route)))
+	}
+
+	if r.options.responseValidation != ResponseValidationDisabled && hasDocumentedResponses(operation) {
+		validatingRouter, ok := r.router.(apirouter.ResponseValidatingRouter[MiddlewareFunc])
+		if !ok {
+			return nil, ErrValidationNotSupported
+		}
+		onError := r.options.responseValidationErrorHandler
+		if onError == nil {
+			onError = logResponseValidationError
+		}
+		validator := newResponseValidator(route, r.options.responseValidation, onError)
+		middleware = append(middleware, validatingRouter.ResponseValidationMiddleware(validator))
+	}
+
+	return middleware, nil
+}
+
+// hasDocumentedResponses reports whether operation documents its responses,
+// other than with the empty default response of the operations without any.
+func hasDocumentedResponses(operation *openapi3.Operation) bool {
+	if operation.Responses == nil {
+		return false
+	}
+	for status, response := range operation.Responses.Map() {
+		if status != "default" || response.Ref != "" || response.Value == nil {
+			return true
+		}
+		value := response.Value
+		if len(value.Content) > 0 || len(value.Headers) > 0 || (value.Description != nil && *value.Description != "") {
+			return true
+		}
+	}
+	return false
+}
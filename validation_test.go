@@ -0,0 +1,334 @@
+package swagger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.lumeweb.com/gswagger/apirouter"
+	"go.lumeweb.com/gswagger/support/gorilla"
+)
+
+type gorillaAPIRouter = apirouter.Router[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]
+
+// notValidatingRouter hides the optional interfaces implemented by the gorilla router
+type notValidatingRouter struct {
+	gorillaAPIRouter
+}
+
+func setupValidatingRouter(t *testing.T) (*TestRouter, *mux.Router) {
+	t.Helper()
+
+	muxRouter := mux.NewRouter()
+	router, err := NewRouter(gorilla.NewRouter(muxRouter), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+		Context:          context.Background(),
+		Openapi:          getBaseSwagger(t),
+		ValidateRequests: true,
+	})
+	require.NoError(t, err)
+
+	return router, muxRouter
+}
+
+func TestRequestValidation(t *testing.T) {
+	type User struct {
+		Name string `json:"name" jsonschema:"required,minLength=1"`
+		Age  int    `json:"age,omitempty" jsonschema:"minimum=0"`
+	}
+
+	router, muxRouter := setupValidatingRouter(t)
+
+	_, err := router.AddRoute(http.MethodPost, "/users/{userId}", okHandler, Definitions{
+		PathParams: ParameterValue{
+			"userId": {Schema: &Schema{Value: 0}},
+		},
+		Querystring: ParameterValue{
+			"limit": {Schema: &Schema{Value: 0}},
+		},
+		Headers: ParameterValue{
+			"X-Request-Id": {Schema: &Schema{Value: ""}, Required: true},
+		},
+		RequestBody: &ContentValue{
+			Content: Content{
+				jsonType: {Value: User{}},
+			},
+			Required: true,
+		},
+		Responses: map[int]ContentValue{
+			200: {Content: Content{"text/plain": {Value: ""}}},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, router.GenerateAndExposeOpenapi())
+
+	doRequest := func(t *testing.T, target string, headers map[string]string, body string) *http.Response {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", jsonType)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		muxRouter.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	t.Run("valid request reaches the handler", func(t *testing.T) {
+		resp := doRequest(t, "/users/42?limit=10", map[string]string{"X-Request-Id": "abc"}, `{"name":"Jane","age":3}`)
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "OK", readBody(t, resp.Body))
+	})
+
+	t.Run("invalid parameters and body are reported together", func(t *testing.T) {
+		resp := doRequest(t, "/users/foo?limit=bar", nil, `{"age":-1}`)
+
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+		var validationErr RequestValidationError
+		require.NoError(t, json.Unmarshal([]byte(readBody(t, resp.Body)), &validationErr))
+		require.Equal(t, "request validation failed", validationErr.Message)
+
+		locations := map[string]string{}
+		for _, issue := range validationErr.Errors {
+			locations[issue.In] = issue.Name
+			require.NotEmpty(t, issue.Reason)
+		}
+		require.Equal(t, map[string]string{
+			"path":   "userId",
+			"query":  "limit",
+			"header": "X-Request-Id",
+			"body":   "",
+		}, locations)
+	})
+
+	t.Run("missing required body is rejected", func(t *testing.T) {
+		resp := doRequest(t, "/users/42", map[string]string{"X-Request-Id": "abc"}, "")
+
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("routes of groups are validated", func(t *testing.T) {
+		group, err := router.Group("/v1")
+		require.NoError(t, err)
+
+		_, err = group.AddRoute(http.MethodGet, "/items/{itemId}", okHandler, Definitions{
+			PathParams: ParameterValue{
+				"itemId": {Schema: &Schema{Value: 0}},
+			},
+		})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		muxRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/items/foo", nil))
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+		w = httptest.NewRecorder()
+		muxRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/items/1", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	})
+}
+
+func TestRequestValidationNotSupported(t *testing.T) {
+	t.Run("NewRouter fails if the router does not support validation", func(t *testing.T) {
+		_, err := NewRouter[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route](notValidatingRouter{gorilla.NewRouter(mux.NewRouter())}, Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Openapi:          getBaseSwagger(t),
+			ValidateRequests: true,
+		})
+		require.ErrorIs(t, err, ErrValidationNotSupported)
+	})
+
+	t.Run("validation is not required when disabled", func(t *testing.T) {
+		_, err := NewRouter[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route](notValidatingRouter{gorilla.NewRouter(mux.NewRouter())}, Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Openapi: getBaseSwagger(t),
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestNewRequestValidationError(t *testing.T) {
+	err := newRequestValidationError(openapi3.MultiError{
+		errors.New("generic failure"),
+	})
+
+	require.Equal(t, []RequestValidationIssue{{In: "request", Reason: "generic failure"}}, err.Errors)
+	require.Equal(t, "request validation failed: request: generic failure", err.Error())
+	require.Equal(t, http.StatusBadRequest, err.StatusCode())
+
+	status, body := apirouter.ErrorResponse(err)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.JSONEq(t, `{"message":"request validation failed","errors":[{"in":"request","reason":"generic failure"}]}`, string(body))
+}
+
+func TestResponseValidation(t *testing.T) {
+	type User struct {
+		Name string `json:"name" jsonschema:"required"`
+	}
+
+	setup := func(t *testing.T, mode ResponseValidationMode) (*mux.Router, *[]error) {
+		t.Helper()
+
+		var reported []error
+		muxRouter := mux.NewRouter()
+		router, err := NewRouter(gorilla.NewRouter(muxRouter), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Context:            context.Background(),
+			Openapi:            getBaseSwagger(t),
+			ResponseValidation: mode,
+			ResponseValidationErrorHandler: func(req *http.Request, err error) {
+				reported = append(reported, err)
+			},
+		})
+		require.NoError(t, err)
+
+		handler := func(w http.ResponseWriter, req *http.Request) {
+			switch req.URL.Query().Get("case") {
+			case "undocumented-status":
+				w.WriteHeader(http.StatusTeapot)
+			case "invalid-body":
+				w.Header().Set("Content-Type", jsonType)
+				w.Write([]byte(`{"name":3}`))
+			case "invalid-content-type":
+				w.Header().Set("Content-Type", "text/plain")
+				w.Write([]byte(`Jane`))
+			default:
+				w.Header().Set("Content-Type", jsonType)
+				w.Write([]byte(`{"name":"Jane"}`))
+			}
+		}
+		_, err = router.AddRoute(http.MethodGet, "/users/{userId}", handler, Definitions{
+			Responses: map[int]ContentValue{
+				200: {Content: Content{jsonType: {Value: User{}}}},
+			},
+		})
+		require.NoError(t, err)
+		_, err = router.AddRoute(http.MethodGet, "/health", handler, Definitions{})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		return muxRouter, &reported
+	}
+
+	doRequest := func(muxRouter *mux.Router, testCase string) *http.Response {
+		w := httptest.NewRecorder()
+		muxRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/1?case="+testCase, nil))
+		return w.Result()
+	}
+
+	t.Run("valid response is sent unchanged", func(t *testing.T) {
+		muxRouter, reported := setup(t, ResponseValidationFail)
+
+		resp := doRequest(muxRouter, "valid")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.JSONEq(t, `{"name":"Jane"}`, readBody(t, resp.Body))
+		require.Empty(t, *reported)
+	})
+
+	t.Run("fail mode replaces invalid responses", func(t *testing.T) {
+		for _, testCase := range []string{"undocumented-status", "invalid-body", "invalid-content-type"} {
+			t.Run(testCase, func(t *testing.T) {
+				muxRouter, reported := setup(t, ResponseValidationFail)
+
+				resp := doRequest(muxRouter, testCase)
+				require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+				require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+				var validationErr ResponseValidationError
+				require.NoError(t, json.Unmarshal([]byte(readBody(t, resp.Body)), &validationErr))
+				require.Equal(t, "response validation failed", validationErr.Message)
+				require.Equal(t, http.MethodGet, validationErr.Method)
+				require.Equal(t, "/users/{userId}", validationErr.Path)
+				require.NotEmpty(t, validationErr.Errors)
+
+				require.Len(t, *reported, 1)
+			})
+		}
+	})
+
+	t.Run("log mode reports invalid responses without changing them", func(t *testing.T) {
+		muxRouter, reported := setup(t, ResponseValidationLog)
+
+		resp := doRequest(muxRouter, "undocumented-status")
+		require.Equal(t, http.StatusTeapot, resp.StatusCode)
+
+		require.Len(t, *reported, 1)
+		var validationErr *ResponseValidationError
+		require.ErrorAs(t, (*reported)[0], &validationErr)
+		require.Equal(t, http.StatusTeapot, validationErr.Status)
+		require.Equal(t, "response validation failed for GET /users/{userId} with status 418: status is not supported", validationErr.Error())
+	})
+
+	t.Run("routes without documented responses are not checked", func(t *testing.T) {
+		muxRouter, reported := setup(t, ResponseValidationFail)
+
+		w := httptest.NewRecorder()
+		muxRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health?case=undocumented-status", nil))
+		require.Equal(t, http.StatusTeapot, w.Code)
+		require.Empty(t, *reported)
+	})
+
+	t.Run("NewRouter fails if the router does not support response validation", func(t *testing.T) {
+		_, err := NewRouter[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route](notValidatingRouter{gorilla.NewRouter(mux.NewRouter())}, Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Openapi:            getBaseSwagger(t),
+			ResponseValidation: ResponseValidationLog,
+		})
+		require.ErrorIs(t, err, ErrValidationNotSupported)
+	})
+}
+
+func TestValidationConcurrentFirstRequests(t *testing.T) {
+	type Member struct {
+		Name string `json:"name" jsonschema:"required"`
+	}
+	type Team struct {
+		Members []Member `json:"members"`
+	}
+
+	muxRouter := mux.NewRouter()
+	router, err := NewRouter(gorilla.NewRouter(muxRouter), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+		Context:            context.Background(),
+		Openapi:            getBaseSwagger(t),
+		ValidateRequests:   true,
+		ResponseValidation: ResponseValidationFail,
+	})
+	require.NoError(t, err)
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", jsonType)
+		w.Write([]byte(`{"members":[{"name":"Jane"}]}`))
+	}
+	paths := []string{"/teams", "/squads", "/crews", "/bands"}
+	for _, path := range paths {
+		_, err := router.AddRoute(http.MethodPost, path, handler, Definitions{
+			RequestBody: &ContentValue{Content: Content{jsonType: {Value: Team{}}}},
+			Responses: map[int]ContentValue{
+				200: {Content: Content{jsonType: {Value: Team{}}}},
+			},
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, router.GenerateAndExposeOpenapi())
+
+	// Run with -race: the first requests of the routes resolve their references concurrently
+	statuses := make(chan int, len(paths))
+	for _, path := range paths {
+		go func() {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"members":[{"name":"Jane"}]}`))
+			req.Header.Set("Content-Type", jsonType)
+			muxRouter.ServeHTTP(w, req)
+			statuses <- w.Code
+		}()
+	}
+	for range paths {
+		require.Equal(t, http.StatusOK, <-statuses)
+	}
+}
@@ -0,0 +1,236 @@
+package swagger
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+)
+
+// validatorFormats maps the go-playground/validator tags to the matching schema format.
+var validatorFormats = map[string]string{
+	"email":        "email",
+	"url":          "uri",
+	"uri":          "uri",
+	"http_url":     "uri",
+	"uuid":         "uuid",
+	"uuid3":        "uuid",
+	"uuid4":        "uuid",
+	"uuid5":        "uuid",
+	"uuid_rfc4122": "uuid",
+	"ipv4":         "ipv4",
+	"ip4_addr":     "ipv4",
+	"ipv6":         "ipv6",
+	"ip6_addr":     "ipv6",
+	"hostname":     "hostname",
+	"fqdn":         "hostname",
+}
+
+// validatorPatterns maps the go-playground/validator tags to the matching schema pattern.
+var validatorPatterns = map[string]string{
+	"alpha":       `^[a-zA-Z]+$`,
+	"alphanum":    `^[a-zA-Z0-9]+$`,
+	"numeric":     `^[-+]?[0-9]+(?:\.[0-9]+)?$`,
+	"number":      `^[0-9]+$`,
+	"hexadecimal": `^(0[xX])?[0-9a-fA-F]+$`,
+	"lowercase":   `^[^A-Z]*$`,
+	"uppercase":   `^[^a-z]*$`,
+}
+
+// hasValidatorTags reports whether a field of the struct t, or promoted from
+// its embedded structs, has a validator tag.
+func hasValidatorTags(t reflect.Type, tagName, validatorTag string) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for _, f := range promotedFields(t, tagName) {
+		if rules := f.field.Tag.Get(validatorTag); rules != "" && rules != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// applyValidatorTags adds to the schema of the struct t the constraints
+// described by the validator tags of its fields.
+func applyValidatorTags(schema *jsonschema.Schema, t reflect.Type, tagName, validatorTag string) {
+	if schema.Properties == nil {
+		return
+	}
+	for _, f := range promotedFields(t, tagName) {
+		rules := f.field.Tag.Get(validatorTag)
+		if rules == "" || rules == "-" {
+			continue
+		}
+		property, ok := schema.Properties.Get(f.name)
+		if !ok {
+			continue
+		}
+		if applyValidatorRules(property, strings.Split(rules, ",")) && !slices.Contains(schema.Required, f.name) {
+			schema.Required = append(schema.Required, f.name)
+		}
+	}
+}
+
+// applyValidatorRules adds the constraints described by rules to schema,
+// and reports whether the field is required. Rules following dive apply to
+// the items of an array. Unknown rules, alternatives and rules with invalid
+// parameters are ignored, since they cannot be documented.
+func applyValidatorRules(schema *jsonschema.Schema, rules []string) bool {
+	required := false
+	for i, rule := range rules {
+		if strings.Contains(rule, "|") {
+			continue
+		}
+		name, param, _ := strings.Cut(rule, "=")
+		param = strings.NewReplacer("0x2C", ",", "0x7C", "|").Replace(param)
+
+		switch name {
+		case "required":
+			required = true
+			continue
+		case "dive":
+			if schema.Items != nil {
+				applyValidatorRules(schema.Items, rules[i+1:])
+			}
+			return required
+		}
+
+		// Constraints can not be added next to a reference
+		if schema.Ref != "" {
+			continue
+		}
+		switch name {
+		case "min", "gte":
+			setValidatorBound(schema, param, lowerBound)
+		case "gt":
+			setValidatorBound(schema, param, exclusiveLowerBound)
+		case "max", "lte":
+			setValidatorBound(schema, param, upperBound)
+		case "lt":
+			setValidatorBound(schema, param, exclusiveUpperBound)
+		case "len", "eq":
+			if name == "len" || schema.Type == "integer" || schema.Type == "number" {
+				setValidatorBound(schema, param, lowerBound)
+				setValidatorBound(schema, param, upperBound)
+			}
+		case "oneof":
+			if enum := validatorEnum(schema.Type, param); enum != nil {
+				schema.Enum = enum
+			}
+		default:
+			if format, ok := validatorFormats[name]; ok && schema.Type == "string" {
+				schema.Format = format
+			} else if pattern, ok := validatorPatterns[name]; ok && schema.Type == "string" {
+				schema.Pattern = pattern
+			}
+		}
+	}
+	return required
+}
+
+type validatorBound int
+
+const (
+	lowerBound validatorBound = iota
+	exclusiveLowerBound
+	upperBound
+	exclusiveUpperBound
+)
+
+// setValidatorBound sets the bound of schema described by param: a length for
+// strings, a number of items for arrays, of properties for objects, and a
+// value for numbers.
+func setValidatorBound(schema *jsonschema.Schema, param string, bound validatorBound) {
+	switch schema.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(param, 64); err != nil {
+			return
+		}
+		switch bound {
+		case lowerBound:
+			schema.Minimum = json.Number(param)
+		case exclusiveLowerBound:
+			schema.ExclusiveMinimum = json.Number(param)
+		case upperBound:
+			schema.Maximum = json.Number(param)
+		case exclusiveUpperBound:
+			schema.ExclusiveMaximum = json.Number(param)
+		}
+		return
+	}
+
+	n, err := strconv.ParseUint(param, 10, 64)
+	if err != nil {
+		return
+	}
+	switch bound {
+	case exclusiveLowerBound:
+		n++
+	case exclusiveUpperBound:
+		if n == 0 {
+			return
+		}
+		n--
+	}
+
+	var lower, upper **uint64
+	switch schema.Type {
+	case "string":
+		lower, upper = &schema.MinLength, &schema.MaxLength
+	case "array":
+		lower, upper = &schema.MinItems, &schema.MaxItems
+	case "object":
+		lower, upper = &schema.MinProperties, &schema.MaxProperties
+	default:
+		return
+	}
+	if bound == lowerBound || bound == exclusiveLowerBound {
+		*lower = &n
+	} else {
+		*upper = &n
+	}
+}
+
+// validatorEnum returns the values of a oneof rule, separated by spaces and
+// optionally quoted, converted to the schema type.
+func validatorEnum(schemaType, param string) []any {
+	var values []string
+	for rest := strings.TrimSpace(param); rest != ""; rest = strings.TrimSpace(rest) {
+		if rest[0] == '\'' {
+			if end := strings.IndexByte(rest[1:], '\''); end >= 0 {
+				values = append(values, rest[1:end+1])
+				rest = rest[end+2:]
+				continue
+			}
+		}
+		value, remaining, _ := strings.Cut(rest, " ")
+		values = append(values, value)
+		rest = remaining
+	}
+
+	enum := make([]any, 0, len(values))
+	for _, value := range values {
+		switch schemaType {
+		case "integer":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil
+			}
+			enum = append(enum, n)
+		case "number":
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return nil
+			}
+			enum = append(enum, json.Number(value))
+		case "string":
+			enum = append(enum, value)
+		default:
+			return nil
+		}
+	}
+	return enum
+}
@@ -0,0 +1,109 @@
+package swagger
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.lumeweb.com/gswagger/support/gorilla"
+)
+
+type CreateUserRequest struct {
+	Name     string            `json:"name" validate:"required,min=1,max=64"`
+	Email    string            `json:"email,omitempty" validate:"required,email"`
+	Role     string            `json:"role" validate:"oneof=admin 'power user' guest"`
+	Age      int               `json:"age,omitempty" validate:"gte=18,lt=130"`
+	Score    float64           `json:"score" validate:"gt=0,oneof=0.5 1.5"`
+	Username string            `json:"username" validate:"alphanum,len=8"`
+	Tags     []string          `json:"tags" validate:"max=10,dive,min=2"`
+	Labels   map[string]string `json:"labels" validate:"min=1"`
+	Website  string            `json:"website" validate:"omitempty,url|email"`
+	Address  Address           `json:"address" validate:"required"`
+}
+
+type Address struct {
+	Street string `json:"street" validate:"required,max=128"`
+}
+
+func TestValidatorTags(t *testing.T) {
+	setup := func(t *testing.T, validatorTag string) *TestRouter {
+		t.Helper()
+
+		router, err := NewRouter(gorilla.NewRouter(mux.NewRouter()), Options[gorilla.HandlerFunc, mux.MiddlewareFunc, gorilla.Route]{
+			Openapi:      getBaseSwagger(t),
+			ValidatorTag: validatorTag,
+		})
+		require.NoError(t, err)
+		return router
+	}
+	schemaJSON := func(t *testing.T, router *TestRouter, name string) string {
+		t.Helper()
+
+		data, err := json.Marshal(router.GetSwaggerSchema().Components.Schemas[name].Value)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("validator tags are translated into constraints", func(t *testing.T) {
+		router := setup(t, "validate")
+
+		_, err := router.AddRoute(http.MethodPost, "/users", okHandler, Definitions{
+			RequestBody: &ContentValue{Content: Content{jsonType: {Value: CreateUserRequest{}}}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, router.GenerateAndExposeOpenapi())
+
+		require.JSONEq(t, `{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"name": {"type": "string", "minLength": 1, "maxLength": 64},
+				"email": {"type": "string", "format": "email"},
+				"role": {"type": "string", "enum": ["admin", "power user", "guest"]},
+				"age": {"type": "integer", "minimum": 18, "maximum": 130, "exclusiveMaximum": true},
+				"score": {"type": "number", "minimum": 0, "exclusiveMinimum": true, "enum": [0.5, 1.5]},
+				"username": {"type": "string", "pattern": "^[a-zA-Z0-9]+$", "minLength": 8, "maxLength": 8},
+				"tags": {"type": "array", "maxItems": 10, "items": {"type": "string", "minLength": 2}},
+				"labels": {"type": "object", "minProperties": 1, "additionalProperties": {"type": "string"}},
+				"website": {"type": "string"},
+				"address": {"$ref": "#/components/schemas/Address"}
+			},
+			"required": ["name", "role", "score", "username", "tags", "labels", "website", "address", "email"]
+		}`, schemaJSON(t, router, "CreateUserRequest"))
+		require.JSONEq(t, `{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"street": {"type": "string", "maxLength": 128}
+			},
+			"required": ["street"]
+		}`, schemaJSON(t, router, "Address"))
+	})
+
+	t.Run("custom tag name", func(t *testing.T) {
+		router := setup(t, "binding")
+
+		schema, err := router.getSchemaFromInterface(struct {
+			Name  string `json:"name" binding:"max=3"`
+			Email string `json:"email" validate:"email"`
+		}{}, false)
+		require.NoError(t, err)
+
+		data, err := json.Marshal(schema.Value.Properties)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"name": {"type": "string", "maxLength": 3},
+			"email": {"type": "string"}
+		}`, string(data))
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		router := setup(t, "")
+
+		_, err := router.getSchemaFromInterface(Address{}, false)
+		require.NoError(t, err)
+		require.NotContains(t, schemaJSON(t, router, "Address"), "maxLength")
+	})
+}